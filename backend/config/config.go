@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"time"
 )
 
 // Config stores all configuration settings
@@ -14,9 +16,42 @@ type Config struct {
 	OpenRouterAPIKey string
 	OpenRouterModel  string
 	
+	// LLM configuration
+	LLMProvider    string // "openrouter" or "openai" for any OpenAI-compatible server
+	LLMBaseURL     string
+	LLMAPIKey      string
+	LLMModel       string
+	LLMTemperature float64
+	LLMMaxTokens   int
+	LLMTimeout     time.Duration
+	LLMContextWindow int // In tokens; 0 looks it up from the model name
+	
+	// Chat sessions are summarized once SummarizeAfter messages have piled up
+	// since the last summary, keeping the latest KeepUnsummarized out of it
+	SummarizeAfter   int
+	KeepUnsummarized int
+	
+	// Embedding configuration
+	EmbeddingProvider   string // "openai" for an OpenAI-compatible API, "hash" for the offline embedder
+	EmbeddingBaseURL    string
+	EmbeddingAPIKey     string
+	EmbeddingModel      string
+	EmbeddingDimensions int
+	
+	// Document chunking, in characters
+	ChunkSize    int
+	ChunkOverlap int
+	
+	// Let the LLM rerank retrieved documents
+	RerankDocuments bool
+	
 	// JWT configuration
 	JWTSecret        string
 	
+	// Password login lockout
+	MaxLoginAttempts     int
+	LoginLockoutDuration time.Duration
+	
 	// OAuth configuration
 	GoogleClientID           string
 	GoogleClientSecret       string
@@ -44,7 +79,21 @@ func NewConfig() *Config {
 		LogLevel:         "debug",
 		OpenRouterAPIKey: "",
 		OpenRouterModel:  "anthropic/claude-3-opus:beta", // Default to a powerful model
+		LLMProvider:      "openrouter",
+		LLMBaseURL:       "http://localhost:11434/v1",
+		LLMTemperature:   0.7,
+		LLMMaxTokens:     1000, // Lower token limit to ensure it stays within free tier
+		LLMTimeout:       30 * time.Second,
+		SummarizeAfter:   20,
+		KeepUnsummarized: 10,
+		EmbeddingBaseURL:    "https://openrouter.ai/api/v1",
+		EmbeddingModel:      "openai/text-embedding-3-small",
+		EmbeddingDimensions: 1536,
+		ChunkSize:           1000,
+		ChunkOverlap:        150,
 		JWTSecret:        "your-secret-key-change-in-production",
+		MaxLoginAttempts:     5,
+		LoginLockoutDuration: 15 * time.Minute,
 		FrontendURL:      "http://localhost:3000",
 		GoogleRedirectURL: "http://localhost:8080/api/auth/google/callback",
 		GoogleRegisterRedirectURL: "http://localhost:8080/api/auth/google/register/callback",
@@ -95,11 +144,124 @@ func LoadConfigFromEnv() *Config {
 		config.OpenRouterModel = model
 	}
 	
+	// LLM
+	if provider := os.Getenv("LLM_PROVIDER"); provider != "" {
+		config.LLMProvider = provider
+	}
+	
+	if baseURL := os.Getenv("LLM_BASE_URL"); baseURL != "" {
+		config.LLMBaseURL = baseURL
+	}
+	
+	if apiKey := os.Getenv("LLM_API_KEY"); apiKey != "" {
+		config.LLMAPIKey = apiKey
+	}
+	
+	// The Open Router model is the default for every provider
+	config.LLMModel = config.OpenRouterModel
+	if model := os.Getenv("LLM_MODEL"); model != "" {
+		config.LLMModel = model
+	}
+	
+	if temperature := os.Getenv("LLM_TEMPERATURE"); temperature != "" {
+		if t, err := strconv.ParseFloat(temperature, 64); err == nil {
+			config.LLMTemperature = t
+		}
+	}
+	
+	if maxTokens := os.Getenv("LLM_MAX_TOKENS"); maxTokens != "" {
+		if m, err := strconv.Atoi(maxTokens); err == nil && m > 0 {
+			config.LLMMaxTokens = m
+		}
+	}
+	
+	if timeout := os.Getenv("LLM_TIMEOUT_SECONDS"); timeout != "" {
+		if t, err := strconv.Atoi(timeout); err == nil && t > 0 {
+			config.LLMTimeout = time.Duration(t) * time.Second
+		}
+	}
+	
+	if window := os.Getenv("LLM_CONTEXT_WINDOW"); window != "" {
+		if w, err := strconv.Atoi(window); err == nil && w > 0 {
+			config.LLMContextWindow = w
+		}
+	}
+	
+	if after := os.Getenv("SUMMARIZE_AFTER_MESSAGES"); after != "" {
+		if a, err := strconv.Atoi(after); err == nil {
+			config.SummarizeAfter = a
+		}
+	}
+	
+	if keep := os.Getenv("KEEP_UNSUMMARIZED_MESSAGES"); keep != "" {
+		if k, err := strconv.Atoi(keep); err == nil {
+			config.KeepUnsummarized = k
+		}
+	}
+	
+	// Embeddings
+	if provider := os.Getenv("EMBEDDING_PROVIDER"); provider != "" {
+		config.EmbeddingProvider = provider
+	}
+	
+	if baseURL := os.Getenv("EMBEDDING_BASE_URL"); baseURL != "" {
+		config.EmbeddingBaseURL = baseURL
+	}
+	
+	// Fall back to the Open Router key, since Open Router serves embeddings too
+	config.EmbeddingAPIKey = config.OpenRouterAPIKey
+	if apiKey := os.Getenv("EMBEDDING_API_KEY"); apiKey != "" {
+		config.EmbeddingAPIKey = apiKey
+	}
+	
+	if model := os.Getenv("EMBEDDING_MODEL"); model != "" {
+		config.EmbeddingModel = model
+	}
+	
+	if dims := os.Getenv("EMBEDDING_DIMENSIONS"); dims != "" {
+		if d, err := strconv.Atoi(dims); err == nil {
+			config.EmbeddingDimensions = d
+		}
+	}
+	
+	// Document chunking
+	if size := os.Getenv("CHUNK_SIZE"); size != "" {
+		if s, err := strconv.Atoi(size); err == nil {
+			config.ChunkSize = s
+		}
+	}
+	
+	if overlap := os.Getenv("CHUNK_OVERLAP"); overlap != "" {
+		if o, err := strconv.Atoi(overlap); err == nil {
+			config.ChunkOverlap = o
+		}
+	}
+	
+	// Retrieval
+	if rerank := os.Getenv("RERANK_DOCUMENTS"); rerank != "" {
+		if r, err := strconv.ParseBool(rerank); err == nil {
+			config.RerankDocuments = r
+		}
+	}
+	
 	// JWT Secret
 	if jwtSecret := os.Getenv("JWT_SECRET"); jwtSecret != "" {
 		config.JWTSecret = jwtSecret
 	}
 	
+	// Login lockout
+	if attempts := os.Getenv("LOGIN_MAX_ATTEMPTS"); attempts != "" {
+		if a, err := strconv.Atoi(attempts); err == nil && a > 0 {
+			config.MaxLoginAttempts = a
+		}
+	}
+	
+	if lockout := os.Getenv("LOGIN_LOCKOUT_MINUTES"); lockout != "" {
+		if m, err := strconv.Atoi(lockout); err == nil && m > 0 {
+			config.LoginLockoutDuration = time.Duration(m) * time.Minute
+		}
+	}
+	
 	// Google OAuth
 	if clientID := os.Getenv("GOOGLE_CLIENT_ID"); clientID != "" {
 		config.GoogleClientID = clientID
@@ -150,4 +312,29 @@ func LoadConfigFromEnv() *Config {
 	}
 	
 	return config
+}
+
+// MaxEmbeddingDimensions is the largest vector pgvector can build an ivfflat index on
+const MaxEmbeddingDimensions = 2000
+
+// Validate reports settings that would break the server at run time rather
+// than at startup
+func (c *Config) Validate() error {
+	if c.SummarizeAfter <= 0 {
+		return fmt.Errorf("SUMMARIZE_AFTER_MESSAGES must be positive, got %d", c.SummarizeAfter)
+	}
+	if c.KeepUnsummarized < 0 || c.KeepUnsummarized >= c.SummarizeAfter {
+		return fmt.Errorf("KEEP_UNSUMMARIZED_MESSAGES must be at least 0 and less than SUMMARIZE_AFTER_MESSAGES (%d), got %d", c.SummarizeAfter, c.KeepUnsummarized)
+	}
+	if c.EmbeddingDimensions <= 0 || c.EmbeddingDimensions > MaxEmbeddingDimensions {
+		return fmt.Errorf("EMBEDDING_DIMENSIONS must be between 1 and %d, got %d", MaxEmbeddingDimensions, c.EmbeddingDimensions)
+	}
+	if c.ChunkSize <= 0 {
+		return fmt.Errorf("CHUNK_SIZE must be positive, got %d", c.ChunkSize)
+	}
+	// The chunker repeats at most half a chunk, so each chunk adds new text
+	if c.ChunkOverlap < 0 || c.ChunkOverlap > c.ChunkSize/2 {
+		return fmt.Errorf("CHUNK_OVERLAP must be between 0 and half of CHUNK_SIZE (%d), got %d", c.ChunkSize/2, c.ChunkOverlap)
+	}
+	return nil
 } 
\ No newline at end of file
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(c *Config)
+		wantErr string
+	}{
+		{name: "defaults", change: func(c *Config) {}},
+		{name: "largest indexable dimension", change: func(c *Config) { c.EmbeddingDimensions = MaxEmbeddingDimensions }},
+		{name: "no dimensions", change: func(c *Config) { c.EmbeddingDimensions = 0 }, wantErr: "EMBEDDING_DIMENSIONS"},
+		{name: "too many dimensions to index", change: func(c *Config) { c.EmbeddingDimensions = 3072 }, wantErr: "EMBEDDING_DIMENSIONS"},
+		{name: "no summary threshold", change: func(c *Config) { c.SummarizeAfter = 0 }, wantErr: "SUMMARIZE_AFTER_MESSAGES"},
+		{name: "keeping every message out of the summary", change: func(c *Config) { c.KeepUnsummarized = c.SummarizeAfter }, wantErr: "KEEP_UNSUMMARIZED_MESSAGES"},
+		{name: "no chunk size", change: func(c *Config) { c.ChunkSize = 0 }, wantErr: "CHUNK_SIZE"},
+		{name: "negative chunk overlap", change: func(c *Config) { c.ChunkOverlap = -1 }, wantErr: "CHUNK_OVERLAP"},
+		{name: "overlap of half a chunk", change: func(c *Config) { c.ChunkSize, c.ChunkOverlap = 1000, 500 }},
+		{name: "overlap of more than half a chunk", change: func(c *Config) { c.ChunkSize, c.ChunkOverlap = 1000, 501 }, wantErr: "CHUNK_OVERLAP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			tt.change(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want it to mention %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -23,6 +24,8 @@ type AuthController struct {
 	oauthRegConfig    *oauth2.Config
 	userRepo          models.UserRepository
 	jwtSecret         string
+	maxLoginAttempts  int
+	lockoutDuration   time.Duration
 }
 
 // RegistrationRequest represents the registration request body
@@ -32,6 +35,12 @@ type RegistrationRequest struct {
 	Password string `json:"password" binding:"required,min=8" example:"password123"`
 }
 
+// LoginRequest represents the email/password login request body
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email" example:"user@example.com"`
+	Password string `json:"password" binding:"required" example:"password123"`
+}
+
 // NewAuthController creates a new auth controller
 func NewAuthController(cfg *config.Config, userRepo models.UserRepository) *AuthController {
 	return &AuthController{
@@ -58,8 +67,10 @@ func NewAuthController(cfg *config.Config, userRepo models.UserRepository) *Auth
 			},
 			Endpoint: google.Endpoint,
 		},
-		userRepo:  userRepo,
-		jwtSecret: cfg.JWTSecret,
+		userRepo:         userRepo,
+		jwtSecret:        cfg.JWTSecret,
+		maxLoginAttempts: cfg.MaxLoginAttempts,
+		lockoutDuration:  cfg.LoginLockoutDuration,
 	}
 }
 
@@ -452,6 +463,101 @@ func (a *AuthController) Register(c *gin.Context) {
 	})
 }
 
+// Login handles email/password login for local accounts
+// @Summary Log in with email and password
+// @Description Authenticate a local account and return JWT tokens. The account is locked temporarily after repeated failed attempts.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body LoginRequest true "Login credentials"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} map[string]string "Invalid input or account uses Google sign-in"
+// @Failure 401 {object} map[string]string "Invalid email or password"
+// @Failure 423 {object} map[string]string "Account temporarily locked"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /auth/login [post]
+func (a *AuthController) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	user, err := a.userRepo.GetByEmail(c.Request.Context(), req.Email)
+	if errors.Is(err, models.ErrUserNotFound) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		return
+	}
+	if err != nil {
+		utils.Error("Failed to look up user for login: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log in"})
+		return
+	}
+
+	// Accounts created through Google have no password to check
+	if user.Password == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This account uses Google sign-in. Please log in with Google."})
+		return
+	}
+
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		a.respondLocked(c, *user.LockedUntil)
+		return
+	}
+
+	if !utils.CheckPassword(req.Password, user.Password) {
+		lockedUntil, err := a.userRepo.RecordFailedLogin(c.Request.Context(), user.ID, a.maxLoginAttempts, a.lockoutDuration)
+		if err != nil {
+			utils.Error("Failed to record failed login: %v", err)
+		} else if lockedUntil != nil && lockedUntil.After(time.Now()) {
+			utils.Warning("Locked account %s after %d failed login attempts", user.ID, a.maxLoginAttempts)
+			a.respondLocked(c, *lockedUntil)
+			return
+		}
+
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		return
+	}
+
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := a.userRepo.ResetFailedLogins(c.Request.Context(), user.ID); err != nil {
+			utils.Error("Failed to reset failed logins: %v", err)
+		}
+	}
+
+	// Generate JWT tokens
+	accessToken, err := a.generateJWT(user.ID, user.Email, 24*time.Hour)
+	if err != nil {
+		utils.Error("Failed to generate access token: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	refreshToken, err := a.generateJWT(user.ID, user.Email, 7*24*time.Hour)
+	if err != nil {
+		utils.Error("Failed to generate refresh token: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, LoginResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		User:         *user,
+		ExpiresIn:    86400, // 24 hours in seconds
+	})
+}
+
+// respondLocked tells the client the account is locked and when to retry
+func (a *AuthController) respondLocked(c *gin.Context, lockedUntil time.Time) {
+	retryAfter := int(time.Until(lockedUntil).Seconds()) + 1
+	c.Header("Retry-After", fmt.Sprintf("%d", retryAfter))
+	c.JSON(http.StatusLocked, gin.H{
+		"error":       "Account is temporarily locked due to too many failed login attempts",
+		"retry_after": retryAfter,
+	})
+}
+
 // GoogleRegister initiates the Google OAuth registration flow
 // @Summary Initiate Google OAuth registration
 // @Description Redirects to Google OAuth consent page with additional scopes for registration
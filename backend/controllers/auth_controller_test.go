@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"resume.in/backend/config"
+	"resume.in/backend/models"
+	"resume.in/backend/utils"
+)
+
+// lockoutRepo is a user repository holding one user, counting failed logins
+// the way PostgresUserRepository does. Methods it does not override panic.
+type lockoutRepo struct {
+	models.UserRepository
+	user      models.User
+	lookupErr error // Returned by GetByEmail when set, like a database failure
+}
+
+func (r *lockoutRepo) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	if r.lookupErr != nil {
+		return nil, r.lookupErr
+	}
+	if email != r.user.Email {
+		return nil, models.ErrUserNotFound
+	}
+	user := r.user
+	return &user, nil
+}
+
+func (r *lockoutRepo) RecordFailedLogin(ctx context.Context, id string, maxAttempts int, lockDuration time.Duration) (*time.Time, error) {
+	r.user.FailedLoginAttempts++
+	if r.user.FailedLoginAttempts >= maxAttempts {
+		lockedUntil := time.Now().Add(lockDuration)
+		r.user.FailedLoginAttempts, r.user.LockedUntil = 0, &lockedUntil
+	}
+	return r.user.LockedUntil, nil
+}
+
+func (r *lockoutRepo) ResetFailedLogins(ctx context.Context, id string) error {
+	r.user.FailedLoginAttempts, r.user.LockedUntil = 0, nil
+	return nil
+}
+
+func TestLogin(t *testing.T) {
+	utils.InitLoggers()
+	gin.SetMode(gin.TestMode)
+
+	const password = "correct horse"
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		hash       string        // Password hash of the account
+		lockedFor  time.Duration // Lock left on the account before the first attempt
+		lookupErr  error         // Error looking up the account
+		email      string
+		attempts   []string // Passwords tried in order
+		want       []int    // Status of each attempt
+		wantFailed int      // Failed attempts counted after the last one
+	}{
+		{
+			name:     "the right password logs in",
+			hash:     hash,
+			attempts: []string{password},
+			want:     []int{http.StatusOK},
+		},
+		{
+			name:       "wrong passwords are counted",
+			hash:       hash,
+			attempts:   []string{"guess", "guess"},
+			want:       []int{http.StatusUnauthorized, http.StatusUnauthorized},
+			wantFailed: 2,
+		},
+		{
+			name:     "the last allowed failure locks the account",
+			hash:     hash,
+			attempts: []string{"guess", "guess", "guess", password},
+			want:     []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusLocked, http.StatusLocked},
+		},
+		{
+			name:     "logging in clears the failed attempts",
+			hash:     hash,
+			attempts: []string{"guess", "guess", password, "guess", "guess"},
+			want: []int{
+				http.StatusUnauthorized, http.StatusUnauthorized, http.StatusOK,
+				http.StatusUnauthorized, http.StatusUnauthorized,
+			},
+			wantFailed: 2,
+		},
+		{
+			name:      "an expired lock does not block logging in",
+			hash:      hash,
+			lockedFor: -time.Minute,
+			attempts:  []string{password},
+			want:      []int{http.StatusOK},
+		},
+		{
+			name:      "a locked account rejects even the right password",
+			hash:      hash,
+			lockedFor: time.Minute,
+			attempts:  []string{password},
+			want:      []int{http.StatusLocked},
+		},
+		{
+			name:     "an unknown email is rejected like a wrong password",
+			hash:     hash,
+			email:    "someone@example.com",
+			attempts: []string{password},
+			want:     []int{http.StatusUnauthorized},
+		},
+		{
+			name:      "a failed lookup is a server error, not a wrong password",
+			hash:      hash,
+			lookupErr: errors.New("connection refused"),
+			attempts:  []string{password},
+			want:      []int{http.StatusInternalServerError},
+		},
+		{
+			name:     "Google accounts have no password to check",
+			attempts: []string{password},
+			want:     []int{http.StatusBadRequest},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &lockoutRepo{user: models.User{ID: "user-1", Email: "ada@example.com", Password: tt.hash}, lookupErr: tt.lookupErr}
+			if tt.lockedFor != 0 {
+				lockedUntil := time.Now().Add(tt.lockedFor)
+				repo.user.LockedUntil = &lockedUntil
+			}
+			controller := NewAuthController(&config.Config{
+				JWTSecret:            "secret",
+				MaxLoginAttempts:     3,
+				LoginLockoutDuration: 15 * time.Minute,
+			}, repo)
+
+			email := tt.email
+			if email == "" {
+				email = repo.user.Email
+			}
+
+			var got []int
+			for _, attempt := range tt.attempts {
+				w := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(w)
+				body := fmt.Sprintf(`{"email": %q, "password": %q}`, email, attempt)
+				c.Request = httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(body))
+				c.Request.Header.Set("Content-Type", "application/json")
+
+				controller.Login(c)
+				got = append(got, w.Code)
+
+				if w.Code == http.StatusLocked && w.Header().Get("Retry-After") == "" {
+					t.Errorf("locked response has no Retry-After header")
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Login() statuses = %v, want %v", got, tt.want)
+			}
+			if repo.user.FailedLoginAttempts != tt.wantFailed {
+				t.Errorf("failed attempts = %d, want %d", repo.user.FailedLoginAttempts, tt.wantFailed)
+			}
+		})
+	}
+}
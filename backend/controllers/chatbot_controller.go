@@ -1,22 +1,32 @@
 package controllers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
-	"strings"
-	"time"
-	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jung-kurt/gofpdf"
 	"resume.in/backend/models"
+	"resume.in/backend/render"
 	"resume.in/backend/utils"
 )
 
 // ChatRequest represents a chat request from the user
 type ChatRequest struct {
-	Query     string `json:"query" binding:"required"`
-	SessionID string `json:"session_id"`
+	Query     string   `json:"query" binding:"required"`
+	SessionID string   `json:"session_id"`
+	Tags      []string `json:"tags"` // Only answer from documents with one of these tags
+}
+
+// searchScope limits the documents used to answer the request to the user's
+// own, leaving out those attached to other sessions
+func (request ChatRequest) searchScope(userID string) models.SearchScope {
+	return models.SearchScope{UserID: userID, SessionID: request.SessionID, Tags: request.Tags}
 }
 
 // ChatbotController handles chatbot-related API endpoints
@@ -33,43 +43,55 @@ func NewChatbotController(chatbotRepo models.ChatbotRepository, resumeRepo model
 	}
 }
 
-// SendMessage handles the chat API endpoint
-// @Summary Send a message to the chatbot
-// @Description Send a message to the chatbot and get a response
-// @Tags chatbot
-// @Accept json
-// @Produce json
-// @Security Bearer
-// @Param request body ChatRequest true "Chat request"
-// @Success 200 {object} map[string]interface{} "Response with session_id, response object, and optional resume_hint"
-// @Failure 400 {object} map[string]interface{} "Invalid request"
-// @Failure 401 {object} map[string]interface{} "Unauthorized"
-// @Failure 500 {object} map[string]interface{} "Internal server error"
-// @Router /chat/message [post]
-func (c *ChatbotController) SendMessage(ctx *gin.Context) {
-	var request ChatRequest
-	if err := ctx.ShouldBindJSON(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
-		return
+// sessionErrorStatus maps a session lookup error to an HTTP status code
+func sessionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrSessionForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrSessionNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
 	}
+}
+
+// sessionTitle derives a session title from the first message of a conversation
+func sessionTitle(query string) string {
+	title := strings.Join(strings.Fields(query), " ")
+	runes := []rune(title)
+	if len(runes) > 60 {
+		title = strings.TrimSpace(string(runes[:60])) + "..."
+	}
+	return title
+}
 
+// startSession assigns a session ID to the request if it has none and starts
+// the session for the user, or checks that they own the existing one. It
+// writes the error response and returns false on failure.
+func (c *ChatbotController) startSession(ctx *gin.Context, userID string, request *ChatRequest) bool {
 	// If session ID is not provided, create a new one
 	if request.SessionID == "" {
 		request.SessionID = utils.GenerateUUID()
 		utils.Info("Created new session ID: %s", request.SessionID)
 	}
 	
-	utils.Info("Processing chat message for session ID: %s", request.SessionID)
-
-	// Process the query
-	response, err := c.chatbotRepo.ProcessQuery(ctx.Request.Context(), request.SessionID, request.Query)
-	if err != nil {
-		utils.Error("Failed to process query: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process query"})
-		return
+	// Start the session for this user, or make sure they own the existing one
+	if _, err := c.chatbotRepo.EnsureSession(ctx.Request.Context(), request.SessionID, userID, sessionTitle(request.Query)); err != nil {
+		if status := sessionErrorStatus(err); status != http.StatusInternalServerError {
+			ctx.JSON(status, gin.H{"error": err.Error()})
+			return false
+		}
+		utils.Error("Failed to start chat session: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start chat session"})
+		return false
 	}
+	
+	return true
+}
 
-	// Check if this has information relevant for resume generation and add a hint
+// chatResponseData builds the body returned for an answered chat message,
+// adding a hint when the query carries information relevant for a resume
+func chatResponseData(request ChatRequest, response models.ChatResponse) gin.H {
 	resumeKeywords := []string{
 		"resume", "CV", "experience", "job", "work", "skill", "education",
 		"qualification", "degree", "university", "college", "project",
@@ -93,10 +115,132 @@ func (c *ChatbotController) SendMessage(ctx *gin.Context) {
 		responseData["resume_message"] = "I've saved this information for your resume. When you're ready, you can generate your resume by sending a request to the generate-resume endpoint."
 	}
 
+	return responseData
+}
+
+// SendMessage handles the chat API endpoint
+// @Summary Send a message to the chatbot
+// @Description Send a message to the chatbot and get a response
+// @Tags chatbot
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body ChatRequest true "Chat request"
+// @Success 200 {object} map[string]interface{} "Response with session_id, response object, and optional resume_hint"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Session belongs to another user"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Failure 503 {object} map[string]interface{} "The LLM failed to answer; nothing was saved"
+// @Router /chat/message [post]
+func (c *ChatbotController) SendMessage(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var request ChatRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	if !c.startSession(ctx, userID, &request) {
+		return
+	}
+
+	utils.Info("Processing chat message for session ID: %s", request.SessionID)
+
+	// Process the query
+	response, err := c.chatbotRepo.ProcessQuery(ctx.Request.Context(), request.searchScope(userID), request.Query)
+	if errors.Is(err, models.ErrLLMUnavailable) {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		utils.Error("Failed to process query: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process query"})
+		return
+	}
+
+	responseData := chatResponseData(request, response)
+
 	// Return the response
 	ctx.JSON(http.StatusOK, responseData)
 }
 
+// SendMessageStream handles the streaming chat API endpoint
+// @Summary Send a message to the chatbot and stream the answer
+// @Description Send a message to the chatbot and receive the answer as server-sent events while it is generated.
+// @Description A "session" event carrying the session_id comes first, then one "delta" event per piece of the answer,
+// @Description then a "done" event with the same body as /chat/message. An "error" event ends the stream on failure.
+// @Description The message and its answer are saved together once the answer is complete. Closing the connection cancels generation and saves neither.
+// @Tags chatbot
+// @Accept json
+// @Produce text/event-stream
+// @Security Bearer
+// @Param request body ChatRequest true "Chat request"
+// @Success 200 {string} string "Server-sent event stream"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Session belongs to another user"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /chat/message/stream [post]
+func (c *ChatbotController) SendMessageStream(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var request ChatRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	if !c.startSession(ctx, userID, &request) {
+		return
+	}
+
+	utils.Info("Streaming chat message for session ID: %s", request.SessionID)
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+	ctx.Status(http.StatusOK)
+
+	send := func(event string, data interface{}) {
+		ctx.SSEvent(event, data)
+		ctx.Writer.Flush()
+	}
+
+	send("session", gin.H{"session_id": request.SessionID})
+
+	// The request context is cancelled when the client disconnects, which
+	// aborts the upstream LLM request as well
+	requestCtx := ctx.Request.Context()
+	response, err := c.chatbotRepo.ProcessQueryStream(requestCtx, request.searchScope(userID), request.Query, func(delta string) error {
+		send("delta", gin.H{"content": delta})
+		return nil
+	})
+	if err != nil {
+		if requestCtx.Err() != nil {
+			utils.Info("Client disconnected from stream for session ID: %s", request.SessionID)
+			return
+		}
+		if errors.Is(err, models.ErrLLMUnavailable) {
+			send("error", gin.H{"error": err.Error()})
+			return
+		}
+		utils.Error("Failed to process query: %v", err)
+		send("error", gin.H{"error": "Failed to process query"})
+		return
+	}
+
+	send("done", chatResponseData(request, response))
+}
+
 // GetChatHistory retrieves the chat history for a session
 // @Summary Get chat history
 // @Description Get the chat history for a specific session
@@ -108,15 +252,27 @@ func (c *ChatbotController) SendMessage(ctx *gin.Context) {
 // @Success 200 {object} map[string]interface{} "Chat history with session_id and messages array"
 // @Failure 400 {object} map[string]interface{} "Invalid request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Session belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Session not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /chat/history/{sessionId} [get]
 func (c *ChatbotController) GetChatHistory(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
 	sessionID := ctx.Param("sessionId")
 	if sessionID == "" {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Session ID is required"})
 		return
 	}
 
+	session, ok := c.requireSession(ctx, userID, sessionID)
+	if !ok {
+		return
+	}
+
 	// Get chat history
 	messages, err := c.chatbotRepo.GetSessionMessages(ctx.Request.Context(), sessionID)
 	if err != nil {
@@ -127,10 +283,59 @@ func (c *ChatbotController) GetChatHistory(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"session_id": sessionID,
+		"title":      session.Title,
 		"messages":   messages,
 	})
 }
 
+// ListSessions lists the current user's chat sessions
+// @Summary List chat sessions
+// @Description Get the current user's chat sessions, most recently active first
+// @Tags chatbot
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} map[string]interface{} "Sessions array"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /chat/sessions [get]
+func (c *ChatbotController) ListSessions(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	sessions, err := c.chatbotRepo.ListSessions(ctx.Request.Context(), userID)
+	if err != nil {
+		utils.Error("Failed to list chat sessions: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list chat sessions"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"sessions": sessions,
+	})
+}
+
+// requireSession loads a session owned by the user, or responds with the
+// matching error status
+func (c *ChatbotController) requireSession(ctx *gin.Context, userID, sessionID string) (models.ChatSession, bool) {
+	session, err := c.chatbotRepo.GetSession(ctx.Request.Context(), userID, sessionID)
+	if err != nil {
+		status := sessionErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			utils.Error("Failed to get chat session: %v", err)
+			ctx.JSON(status, gin.H{"error": "Failed to get chat session"})
+		} else {
+			ctx.JSON(status, gin.H{"error": err.Error()})
+		}
+		return models.ChatSession{}, false
+	}
+	return session, true
+}
+
+// maxDocumentUploadSize limits the size of files uploaded to the knowledge base
+const maxDocumentUploadSize = 10 << 20
+
 // UploadDocumentRequest represents a document upload request
 type UploadDocumentRequest struct {
 	Content  string                 `json:"content" binding:"required"`
@@ -139,105 +344,424 @@ type UploadDocumentRequest struct {
 
 // UploadDocument handles uploading a document to the vector store
 // @Summary Upload a document
-// @Description Upload a document to the vector store for context retrieval
+// @Description Upload a document to the vector store for context retrieval. Send either JSON with the text in content, or a multipart form with a PDF, DOCX, plain text or Markdown file in the file field. For files, the text is extracted and the file name, MIME type, size and uploader are added to the metadata, with the file name as the source shown in chat answers.
+// @Description Documents only answer their uploader's chats. A session_id in the metadata limits a document to that chat session, and tags, a list of strings, let chat requests pick documents by tag.
 // @Tags chatbot
 // @Accept json
+// @Accept multipart/form-data
 // @Produce json
 // @Security Bearer
-// @Param request body UploadDocumentRequest true "Document upload request"
-// @Success 200 {object} map[string]interface{} "Upload status"
+// @Param request body UploadDocumentRequest false "Document upload request (JSON)"
+// @Param file formData file false "PDF, DOCX, TXT or Markdown file, at most 10 MB (multipart)"
+// @Param source formData string false "Source shown in chat answers, defaults to the file name (multipart)"
+// @Param metadata formData string false "Extra metadata as a JSON object (multipart)"
+// @Success 200 {object} map[string]interface{} "Upload status, document_id, chunks and metadata"
 // @Failure 400 {object} map[string]interface{} "Invalid request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Session in the metadata belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Session in the metadata not found"
+// @Failure 422 {object} map[string]interface{} "The file has no extractable text"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /chat/document [post]
 func (c *ChatbotController) UploadDocument(ctx *gin.Context) {
-	var request UploadDocumentRequest
-	if err := ctx.ShouldBindJSON(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+	c.storeDocument(ctx, "")
+}
+
+// ReplaceDocument replaces the content of one of the current user's documents
+// @Summary Replace a document
+// @Description Replace the text and metadata of a knowledge base document with a new upload, in the same JSON or multipart form as POST /chat/document. The old chunks are swapped for the new ones at once, so chat answers never mix the two versions.
+// @Tags chatbot
+// @Accept json
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Param request body UploadDocumentRequest false "Document upload request (JSON)"
+// @Param file formData file false "PDF, DOCX, TXT or Markdown file, at most 10 MB (multipart)"
+// @Param source formData string false "Source shown in chat answers, defaults to the file name (multipart)"
+// @Param metadata formData string false "Extra metadata as a JSON object (multipart)"
+// @Success 200 {object} map[string]interface{} "Upload status, document_id, chunks and metadata"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Document, or the session in the metadata, belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Document, or the session in the metadata, not found"
+// @Failure 422 {object} map[string]interface{} "The file has no extractable text"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /chat/documents/{id} [put]
+func (c *ChatbotController) ReplaceDocument(ctx *gin.Context) {
+	c.storeDocument(ctx, ctx.Param("id"))
+}
+
+// storeDocument reads an uploaded document and stores it for the user as a
+// new document, or in place of documentID
+func (c *ChatbotController) storeDocument(ctx *gin.Context, documentID string) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
 		return
 	}
 
-	// Use the AddDocument method from the interface
-	if err := c.chatbotRepo.AddDocument(ctx.Request.Context(), request.Content, request.Metadata); err != nil {
-		utils.Error("Failed to upload document: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload document"})
+	var content string
+	var metadata map[string]interface{}
+	if strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		if content, metadata, ok = readDocumentFile(ctx); !ok {
+			return
+		}
+	} else {
+		var request UploadDocumentRequest
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+			return
+		}
+		content, metadata = request.Content, request.Metadata
+	}
+	documentID, chunks, err := c.chatbotRepo.AddDocument(ctx.Request.Context(), userID, documentID, content, metadata)
+	if err != nil {
+		documentError(ctx, err, "Failed to upload document")
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"status": "success",
 		"message": "Document uploaded successfully",
+		"document_id": documentID,
+		"chunks": chunks,
+		"metadata": metadata,
 	})
 }
 
+// documentError responds with the status for a document error. Unexpected
+// errors are logged and reported with message.
+func documentError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrDocumentEmpty), errors.Is(err, models.ErrDocumentMetadata):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrSessionForbidden), errors.Is(err, models.ErrSessionNotFound):
+		ctx.JSON(sessionErrorStatus(err), gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrDocumentForbidden):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrDocumentNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		utils.Error("%s: %v", message, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// ListDocuments lists the current user's knowledge base documents
+// @Summary List documents
+// @Description Get the documents the current user uploaded to the knowledge base, most recently uploaded first, without their chunks
+// @Tags chatbot
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} map[string]interface{} "Documents array"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /chat/documents [get]
+func (c *ChatbotController) ListDocuments(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	documents, err := c.chatbotRepo.ListDocuments(ctx.Request.Context(), userID)
+	if err != nil {
+		utils.Error("Failed to list documents: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list documents"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"documents": documents,
+	})
+}
+
+// GetDocument returns one of the current user's documents with its chunks
+// @Summary Get a document
+// @Description Get a knowledge base document with its metadata and the text of its chunks
+// @Tags chatbot
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Success 200 {object} models.KnowledgeDocument
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Document belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Document not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /chat/documents/{id} [get]
+func (c *ChatbotController) GetDocument(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	document, err := c.chatbotRepo.GetDocument(ctx.Request.Context(), userID, ctx.Param("id"))
+	if err != nil {
+		documentError(ctx, err, "Failed to get document")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, document)
+}
+
+// DeleteDocument removes one of the current user's documents
+// @Summary Delete a document
+// @Description Delete a knowledge base document with all its chunks and embeddings, so it is no longer used in chat answers
+// @Tags chatbot
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Document ID"
+// @Success 200 {object} map[string]string "status: deleted"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Document belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Document not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /chat/documents/{id} [delete]
+func (c *ChatbotController) DeleteDocument(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.chatbotRepo.DeleteDocument(ctx.Request.Context(), userID, ctx.Param("id")); err != nil {
+		documentError(ctx, err, "Failed to delete document")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// DeleteDocuments removes all of the current user's documents
+// @Summary Delete all documents
+// @Description Delete every knowledge base document you uploaded, with all their chunks and embeddings. Other users' documents are not affected.
+// @Tags chatbot
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} map[string]interface{} "status: deleted, and the number of deleted documents"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /chat/documents [delete]
+func (c *ChatbotController) DeleteDocuments(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	deleted, err := c.chatbotRepo.DeleteDocuments(ctx.Request.Context(), userID)
+	if err != nil {
+		utils.Error("Failed to delete documents of user %s: %v", userID, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete documents"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "deleted", "deleted": deleted})
+}
+
+// readDocumentFile extracts the text of a multipart document upload and
+// describes the file in its metadata, or responds with an error
+func readDocumentFile(ctx *gin.Context) (string, map[string]interface{}, bool) {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "A document is required in the file field"})
+		return "", nil, false
+	}
+	if fileHeader.Size > maxDocumentUploadSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Documents are limited to %d MB", maxDocumentUploadSize>>20)})
+		return "", nil, false
+	}
+
+	metadata := map[string]interface{}{}
+	if extra := ctx.PostForm("metadata"); extra != "" {
+		if err := json.Unmarshal([]byte(extra), &metadata); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "metadata must be a JSON object"})
+			return "", nil, false
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read document"})
+		return "", nil, false
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxDocumentUploadSize+1))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read document"})
+		return "", nil, false
+	}
+
+	filename := filepath.Base(fileHeader.Filename)
+	mimeType, err := utils.DocumentType(filename, data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", nil, false
+	}
+	content, err := utils.DocumentText(mimeType, data)
+	if errors.Is(err, utils.ErrNoPDFText) || errors.Is(err, utils.ErrEmptyDocument) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return "", nil, false
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", nil, false
+	}
+
+	source := strings.TrimSpace(ctx.PostForm("source"))
+	if source == "" {
+		source = filename
+	}
+	metadata["source"] = source
+	metadata["filename"] = filename
+	metadata["mime_type"] = mimeType
+	metadata["size"] = len(data)
+	return content, metadata, true
+}
+
 // GenerateResumeRequest contains the information needed to generate an ATS resume
 type GenerateResumeRequest struct {
 	SessionID string `json:"session_id" binding:"required"`
 	Query     string `json:"query" binding:"omitempty"` // Make query optional for compatibility with chat requests
+	Save      bool   `json:"save"`                      // Also save the resume to the user's account
+	Format    string `json:"format"`                    // pdf (default), docx, md, html or txt
+	Template  string `json:"template"`                  // PDF template from /templates, or a custom template for text formats
+	render.Options
 }
 
 // GenerateATSResume generates an ATS-optimized resume in PDF format from chat data
 // @Summary Generate ATS Resume
-// @Description Process chat history to generate an ATS-formatted resume in PDF
+// @Description Process chat history to generate an ATS-formatted resume in PDF, or in another format from /templates such as docx. The LLM extracts only facts the user stated; sections without evidence are left empty.
 // @Tags chatbot
 // @Accept json
 // @Produce application/pdf
+// @Produce application/vnd.openxmlformats-officedocument.wordprocessingml.document
 // @Security Bearer
 // @Param request body GenerateResumeRequest true "Generate resume request"
-// @Success 200 {file} binary "Resume PDF file"
+// @Success 200 {file} binary "Resume file"
 // @Failure 400 {object} map[string]interface{} "Invalid request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Session belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Session not found"
+// @Header 200 {string} X-Resume-ID "ID of the saved resume when save is true"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /chat/generate-resume [post]
 func (c *ChatbotController) GenerateATSResume(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	request, session, ok := c.bindResumeRequest(ctx, userID)
+	if !ok {
+		return
+	}
+
+	resumeData, ok := c.extractSessionResume(ctx, userID, request)
+	if !ok {
+		return
+	}
+
+	if request.Save {
+		saved, _, err := c.saveSessionResume(ctx.Request.Context(), userID, session, resumeData)
+		if err != nil {
+			utils.Error("Failed to save resume: %v", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save resume"})
+			return
+		}
+		resumeData = saved
+		ctx.Header("X-Resume-ID", saved.ID)
+	}
+
+	serveResume(ctx, c.resumeRepo, userID, resumeData, request.Format, request.Template, request.Options)
+}
+
+// SaveChatResume extracts a resume from chat data and saves it to the user's account
+// @Summary Save resume from chat
+// @Description Process chat history into a structured resume and save it to the current user's account.
+// @Description The first save from a session creates a resume; later saves from the same session update it.
+// @Description The saved resume can then be edited through /resumes/{id}.
+// @Tags chatbot
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body GenerateResumeRequest true "Save resume request"
+// @Success 200 {object} map[string]interface{} "Updated resume with resume_id and resume"
+// @Success 201 {object} map[string]interface{} "Created resume with resume_id and resume"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Session belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Session not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /chat/save-resume [post]
+func (c *ChatbotController) SaveChatResume(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	request, session, ok := c.bindResumeRequest(ctx, userID)
+	if !ok {
+		return
+	}
+
+	resumeData, ok := c.extractSessionResume(ctx, userID, request)
+	if !ok {
+		return
+	}
+
+	saved, created, err := c.saveSessionResume(ctx.Request.Context(), userID, session, resumeData)
+	if err != nil {
+		utils.Error("Failed to save resume: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save resume"})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+
+	ctx.JSON(status, gin.H{
+		"resume_id": saved.ID,
+		"resume":    saved,
+	})
+}
+
+// bindResumeRequest reads a GenerateResumeRequest and checks that the user owns
+// its session. It writes the error response and returns false on failure.
+func (c *ChatbotController) bindResumeRequest(ctx *gin.Context, userID string) (GenerateResumeRequest, models.ChatSession, bool) {
 	var request GenerateResumeRequest
 	if err := ctx.ShouldBindJSON(&request); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
-		return
+		return request, models.ChatSession{}, false
 	}
 
 	// Validate the sessionID is provided
 	if request.SessionID == "" {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "session_id is required"})
-		return
+		return request, models.ChatSession{}, false
 	}
 
-	utils.Info("Generating resume for session ID: %s", request.SessionID)
+	session, ok := c.requireSession(ctx, userID, request.SessionID)
+	return request, session, ok
+}
+
+// extractSessionResume adds the request's query to the session, if any, and
+// extracts resume data from the session's chat history. It writes the error
+// response and returns false on failure.
+func (c *ChatbotController) extractSessionResume(ctx *gin.Context, userID string, request GenerateResumeRequest) (models.Resume, bool) {
+	utils.Info("Extracting resume for session ID: %s", request.SessionID)
 	
 	// If a query is provided, process it first to add it to the chat history
 	if request.Query != "" {
 		utils.Info("Processing query before generating resume: %s", request.Query)
 		
-		// Create an embedding for the query (simplified for now)
-		embedding := make([]float32, 1536)
-		for i := range embedding {
-			embedding[i] = float32(i % 100) / 100.0
-		}
-		
-		// Save the user message
-		userMsg := models.ChatMessage{
-			SessionID: request.SessionID,
-			Role:      "user",
-			Content:   request.Query,
-			Embedding: embedding,
-			CreatedAt: time.Now(),
-		}
-		
-		_, saveErr := c.chatbotRepo.SaveMessage(ctx.Request.Context(), userMsg)
-		if saveErr != nil {
-			utils.Warning("Failed to save user query to chat history: %v", saveErr)
-			// Continue anyway, we still have the session ID
+		// Answering the query saves it to the history along with the answer
+		scope := models.SearchScope{UserID: userID, SessionID: request.SessionID}
+		_, procErr := c.chatbotRepo.ProcessQuery(ctx.Request.Context(), scope, request.Query)
+		if procErr != nil {
+			utils.Warning("Failed to get chatbot response for query: %v", procErr)
+			// Continue anyway with the history we have
 		} else {
-			utils.Info("Saved query to chat history")
-			
-			// Process the query to get a response from chatbot
-			_, procErr := c.chatbotRepo.ProcessQuery(ctx.Request.Context(), request.SessionID, request.Query)
-			if procErr != nil {
-				utils.Warning("Failed to get chatbot response for query: %v", procErr)
-				// Continue anyway, we don't need the response for resume generation
-			} else {
-				utils.Info("Processed query and saved assistant response")
-			}
+			utils.Info("Processed query and saved the chat turn")
 		}
 	}
 
@@ -246,417 +770,69 @@ func (c *ChatbotController) GenerateATSResume(ctx *gin.Context) {
 	if err != nil {
 		utils.Error("Failed to get chat history: %v", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get chat history"})
-		return
+		return models.Resume{}, false
 	}
 
 	// Check if there are messages
 	if len(messages) == 0 {
 		utils.Warning("No messages found for session %s", request.SessionID)
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "No chat history found for this session"})
-		return
+		return models.Resume{}, false
 	}
 
 	utils.Info("Found %d messages for resume generation", len(messages))
 
 	// Extract resume data from chat messages
-	resumeData, err := c.extractResumeDataFromChat(messages)
+	resumeData, err := c.chatbotRepo.ExtractResume(ctx.Request.Context(), messages)
 	if err != nil {
 		utils.Error("Failed to extract resume data: %v", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to extract resume data from chat"})
-		return
+		return models.Resume{}, false
 	}
 
-	// Generate PDF file
-	pdfPath, err := c.generatePDF(resumeData)
-	if err != nil {
-		utils.Error("Failed to generate PDF: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate PDF"})
-		return
-	}
-
-	utils.Info("Resume PDF generated successfully at: %s", pdfPath)
-
-	// Set filename for download
-	filename := "ATS_Resume_" + resumeData.BasicInfo.Name + ".pdf"
-	filename = strings.ReplaceAll(filename, " ", "_")
-
-	// Serve the file
-	ctx.FileAttachment(pdfPath, filename)
-
-	// Clean up the temporary file after serving
-	go func() {
-		time.Sleep(5 * time.Second)
-		os.Remove(pdfPath)
-	}()
+	return resumeData, true
 }
 
-// extractResumeDataFromChat processes chat messages to extract structured resume data
-func (c *ChatbotController) extractResumeDataFromChat(messages []models.ChatMessage) (models.Resume, error) {
-	// Initialize an empty resume
-	resume := models.Resume{
-		ID:         utils.GenerateUUID(),
-		BasicInfo:  models.BasicInfo{},
-		Experience: []models.Experience{},
-		Education:  []models.Education{},
-		Skills:     []models.Skill{},
-		Projects:   []models.Project{},
-	}
-
-	// Process all chat messages to extract information
-	var allUserContent string
-	for _, msg := range messages {
-		if msg.Role == "user" {
-			allUserContent += msg.Content + " "
-			
-			// Extract basic info
-			if strings.Contains(strings.ToLower(msg.Content), "name is") || strings.Contains(strings.ToLower(msg.Content), "my name") {
-				parts := strings.Split(msg.Content, "is")
-				if len(parts) > 1 {
-					namePart := strings.TrimSpace(parts[1])
-					endIdx := strings.IndexAny(namePart, ".!?")
-					if endIdx > 0 {
-						namePart = namePart[:endIdx]
-					}
-					resume.BasicInfo.Name = strings.TrimSpace(namePart)
-				}
-			}
-			
-			// Extract email
-			if strings.Contains(strings.ToLower(msg.Content), "email") || strings.Contains(strings.ToLower(msg.Content), "@") {
-				words := strings.Fields(msg.Content)
-				for _, word := range words {
-					if strings.Contains(word, "@") && strings.Contains(word, ".") {
-						resume.BasicInfo.Email = strings.Trim(word, ".,;:!?")
-					}
-				}
-			}
-			
-			// Extract phone
-			if strings.Contains(strings.ToLower(msg.Content), "phone") || strings.Contains(strings.ToLower(msg.Content), "contact") {
-				words := strings.Fields(msg.Content)
-				for i, word := range words {
-					if strings.Contains(strings.ToLower(word), "phone") && i < len(words)-1 {
-						resume.BasicInfo.Phone = strings.Trim(words[i+1], ".,;:!?")
-					}
-				}
-			}
-			
-			// Extract skills
-			if strings.Contains(strings.ToLower(msg.Content), "skill") || strings.Contains(strings.ToLower(msg.Content), "know") || strings.Contains(strings.ToLower(msg.Content), "can do") {
-				skillKeywords := []string{"programming", "language", "framework", "software", "tool", "technology"}
-				
-				for _, keyword := range skillKeywords {
-					if strings.Contains(strings.ToLower(msg.Content), keyword) {
-						parts := strings.Split(strings.ToLower(msg.Content), keyword)
-						if len(parts) > 1 {
-							skillsPart := parts[1]
-							endIdx := strings.IndexAny(skillsPart, ".!?")
-							if endIdx > 0 {
-								skillsPart = skillsPart[:endIdx]
-							}
-							
-							skillsList := strings.Split(skillsPart, ",")
-							for _, skill := range skillsList {
-								skill = strings.TrimSpace(skill)
-								if skill != "" && !strings.Contains(strings.ToLower(skill), "and") {
-									// Check if skill already exists
-									exists := false
-									for _, existingSkill := range resume.Skills {
-										if existingSkill.Name == skill {
-											exists = true
-											break
-										}
-									}
-									
-									if !exists {
-										resume.Skills = append(resume.Skills, models.Skill{
-											Name:     strings.TrimSpace(skill),
-											Category: keyword,
-										})
-									}
-								}
-							}
-						}
-					}
-				}
-			}
-			
-			// Extract experience
-			if strings.Contains(strings.ToLower(msg.Content), "work") || strings.Contains(strings.ToLower(msg.Content), "job") || strings.Contains(strings.ToLower(msg.Content), "experience") {
-				lines := strings.Split(msg.Content, ".")
-				for _, line := range lines {
-					if strings.Contains(strings.ToLower(line), "work") || strings.Contains(strings.ToLower(line), "job") || strings.Contains(strings.ToLower(line), "experience") {
-						exp := models.Experience{
-							Description: strings.TrimSpace(line) + ".",
-						}
-						
-						// Try to extract company name
-						companies := []string{"at", "for", "with"}
-						for _, company := range companies {
-							if strings.Contains(strings.ToLower(line), company+" ") {
-								parts := strings.Split(strings.ToLower(line), company+" ")
-								if len(parts) > 1 {
-									companyName := strings.Split(parts[1], " ")[0]
-									exp.Company = strings.TrimSpace(companyName)
-									break
-								}
-							}
-						}
-						
-						// Try to extract position
-						positions := []string{"as a", "as an", "position", "role"}
-						for _, position := range positions {
-							if strings.Contains(strings.ToLower(line), position+" ") {
-								parts := strings.Split(strings.ToLower(line), position+" ")
-								if len(parts) > 1 {
-									positionName := strings.Split(parts[1], " ")[0]
-									exp.Position = strings.TrimSpace(positionName)
-									break
-								}
-							}
-						}
-						
-						// Try to extract dates
-						months := []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December", "Jan", "Feb", "Mar", "Apr", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
-						years := []string{"2020", "2021", "2022", "2023", "2024", "2025"}
-						
-						for _, month := range months {
-							if strings.Contains(line, month) {
-								for _, year := range years {
-									if strings.Contains(line, year) {
-										exp.StartDate = month + " " + year
-										break
-									}
-								}
-							}
-						}
-						
-						if exp.StartDate == "" {
-							exp.StartDate = "2020-01" // Default
-						}
-						
-						if strings.Contains(strings.ToLower(line), "present") || strings.Contains(strings.ToLower(line), "current") || strings.Contains(strings.ToLower(line), "now") {
-							exp.EndDate = "Present"
-						} else {
-							exp.EndDate = "Present" // Default to present
-						}
-						
-						// Check if we have the minimum required info
-						if exp.Description != "" && (exp.Company != "" || exp.Position != "") {
-							if exp.Company == "" {
-								exp.Company = "Unknown Company"
-							}
-							if exp.Position == "" {
-								exp.Position = "Software Developer" // Default
-							}
-							resume.Experience = append(resume.Experience, exp)
-						}
-					}
-				}
-			}
+// saveSessionResume saves a resume extracted from a session to the user's
+// account. The first save creates the resume and links it to the session;
+// later saves update the linked resume. It reports whether a resume was created.
+func (c *ChatbotController) saveSessionResume(ctx context.Context, userID string, session models.ChatSession, resume models.Resume) (models.Resume, bool, error) {
+	if session.ResumeID != "" {
+		resume.ID = session.ResumeID
+		updated, err := c.resumeRepo.Update(userID, session.ResumeID, resume)
+		if err == nil {
+			utils.Info("Updated resume %s from session %s", updated.ID, session.ID)
+			return updated, false, nil
 		}
-	}
-	
-	// Fill in defaults if data couldn't be extracted
-	if resume.BasicInfo.Name == "" {
-		resume.BasicInfo.Name = "Job Applicant"
-	}
-	
-	if resume.BasicInfo.Email == "" {
-		resume.BasicInfo.Email = "applicant@example.com"
-	}
-	
-	// Generate a summary from all user messages
-	if len(allUserContent) > 0 {
-		summary := "Professional with experience in "
-		
-		// Add skills to summary
-		if len(resume.Skills) > 0 {
-			for i, skill := range resume.Skills {
-				if i > 0 && i < len(resume.Skills)-1 {
-					summary += ", "
-				} else if i > 0 {
-					summary += " and "
-				}
-				summary += skill.Name
-			}
-		} else {
-			summary += "software development and technology"
-		}
-		
-		// Add positions to summary
-		if len(resume.Experience) > 0 {
-			summary += ". Previous roles include "
-			for i, exp := range resume.Experience {
-				if i > 0 && i < len(resume.Experience)-1 {
-					summary += ", "
-				} else if i > 0 {
-					summary += " and "
-				}
-				summary += exp.Position
-				if exp.Company != "" {
-					summary += " at " + exp.Company
-				}
-			}
-		}
-		
-		summary += "."
-		resume.Summary = summary
-	} else {
-		resume.Summary = "Professional summary extracted from chat conversation."
-	}
-	
-	// Add a default experience if none was extracted
-	if len(resume.Experience) == 0 {
-		resume.Experience = append(resume.Experience, models.Experience{
-			Company:     "Example Company",
-			Position:    "Software Developer",
-			StartDate:   "2020-01",
-			EndDate:     "Present",
-			Description: "Worked on various software development projects.",
-		})
-	}
-	
-	// Add default education if none was extracted
-	if len(resume.Education) == 0 {
-		resume.Education = append(resume.Education, models.Education{
-			Institution: "University Example",
-			Degree:      "Bachelor's",
-			Field:       "Computer Science",
-			StartDate:   "2016-09",
-			EndDate:     "2020-05",
-		})
-	}
-	
-	// Add some default skills if none were extracted
-	if len(resume.Skills) == 0 {
-		defaultSkills := []string{"Programming", "Problem Solving", "Communication", "Teamwork"}
-		for _, skill := range defaultSkills {
-			resume.Skills = append(resume.Skills, models.Skill{
-				Name:     skill,
-				Category: "General",
-			})
+		if !errors.Is(err, models.ErrResumeNotFound) {
+			return models.Resume{}, false, err
 		}
+		// The user deleted the resume since, so save a fresh one
 	}
 
-	return resume, nil
-}
-
-// generatePDF creates an ATS-optimized PDF resume
-func (c *ChatbotController) generatePDF(resume models.Resume) (string, error) {
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	
-	// Set up fonts
-	pdf.SetFont("Helvetica", "B", 16)
-	
-	// Name and contact info
-	pdf.Cell(190, 10, resume.BasicInfo.Name)
-	pdf.Ln(12)
-	
-	pdf.SetFont("Helvetica", "", 10)
-	if resume.BasicInfo.Email != "" {
-		pdf.Cell(190, 6, "Email: "+resume.BasicInfo.Email)
-		pdf.Ln(6)
-	}
-	if resume.BasicInfo.Phone != "" {
-		pdf.Cell(190, 6, "Phone: "+resume.BasicInfo.Phone)
-		pdf.Ln(6)
-	}
-	if resume.BasicInfo.LinkedIn != "" {
-		pdf.Cell(190, 6, "LinkedIn: "+resume.BasicInfo.LinkedIn)
-		pdf.Ln(6)
-	}
-	
-	// Summary
-	pdf.Ln(4)
-	pdf.SetFont("Helvetica", "B", 12)
-	pdf.Cell(190, 8, "PROFESSIONAL SUMMARY")
-	pdf.Ln(8)
-	pdf.SetFont("Helvetica", "", 10)
-	pdf.MultiCell(190, 5, resume.Summary, "", "", false)
-	
-	// Experience
-	pdf.Ln(4)
-	pdf.SetFont("Helvetica", "B", 12)
-	pdf.Cell(190, 8, "EXPERIENCE")
-	pdf.Ln(8)
-	
-	for _, exp := range resume.Experience {
-		pdf.SetFont("Helvetica", "B", 10)
-		pdf.Cell(190, 6, exp.Position+" | "+exp.Company)
-		pdf.Ln(6)
-		
-		pdf.SetFont("Helvetica", "I", 10)
-		pdf.Cell(190, 6, exp.StartDate+" - "+exp.EndDate)
-		pdf.Ln(6)
-		
-		pdf.SetFont("Helvetica", "", 10)
-		pdf.MultiCell(190, 5, exp.Description, "", "", false)
-		
-		if len(exp.Highlights) > 0 {
-			pdf.Ln(2)
-			for _, highlight := range exp.Highlights {
-				pdf.Cell(5, 5, "•")
-				pdf.Cell(185, 5, highlight)
-				pdf.Ln(5)
-			}
-		}
-		
-		pdf.Ln(4)
+	resume.ID = utils.GenerateUUID()
+	created, err := c.resumeRepo.Create(userID, resume)
+	if err != nil {
+		return models.Resume{}, false, err
 	}
-	
-	// Education
-	if len(resume.Education) > 0 {
-		pdf.SetFont("Helvetica", "B", 12)
-		pdf.Cell(190, 8, "EDUCATION")
-		pdf.Ln(8)
-		
-		for _, edu := range resume.Education {
-			pdf.SetFont("Helvetica", "B", 10)
-			pdf.Cell(190, 6, edu.Degree+" in "+edu.Field)
-			pdf.Ln(6)
-			
-			pdf.SetFont("Helvetica", "", 10)
-			pdf.Cell(190, 6, edu.Institution)
-			pdf.Ln(6)
-			
-			pdf.SetFont("Helvetica", "I", 10)
-			pdf.Cell(190, 6, edu.StartDate+" - "+edu.EndDate)
-			pdf.Ln(8)
+
+	err = c.chatbotRepo.LinkSessionResume(ctx, userID, session.ID, created.ID)
+	if errors.Is(err, models.ErrSessionResumeLinked) {
+		// A concurrent save linked its resume first, so update that one
+		// rather than keeping a duplicate
+		if err := c.resumeRepo.Delete(userID, created.ID); err != nil {
+			utils.Warning("Failed to delete duplicate resume %s: %v", created.ID, err)
 		}
-	}
-	
-	// Skills
-	if len(resume.Skills) > 0 {
-		pdf.Ln(4)
-		pdf.SetFont("Helvetica", "B", 12)
-		pdf.Cell(190, 8, "SKILLS")
-		pdf.Ln(8)
-		
-		pdf.SetFont("Helvetica", "", 10)
-		var skillText string
-		for i, skill := range resume.Skills {
-			skillText += skill.Name
-			if i < len(resume.Skills)-1 {
-				skillText += " • "
-			}
+		linked, err := c.chatbotRepo.GetSession(ctx, userID, session.ID)
+		if err != nil {
+			return models.Resume{}, false, err
 		}
-		pdf.MultiCell(190, 5, skillText, "", "", false)
-	}
-	
-	// Create output directory if it doesn't exist
-	outputDir := filepath.Join("test", "resume_pdfs")
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return "", err
+		return c.saveSessionResume(ctx, userID, linked, resume)
 	}
-	
-	// Save PDF to test folder
-	outputPath := filepath.Join(outputDir, "resume_"+utils.GenerateUUID()+".pdf")
-	err := pdf.OutputFileAndClose(outputPath)
 	if err != nil {
-		return "", err
+		return models.Resume{}, false, fmt.Errorf("failed to link resume to session: %w", err)
 	}
-	
-	return outputPath, nil
-} 
\ No newline at end of file
+
+	utils.Info("Saved resume %s from session %s", created.ID, session.ID)
+	return created, true, nil
+}
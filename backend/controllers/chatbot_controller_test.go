@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"resume.in/backend/models"
+	"resume.in/backend/utils"
+)
+
+// linkingRepo is a chatbot repository whose session may have had a resume
+// linked by a concurrent save. Methods it does not override panic.
+type linkingRepo struct {
+	models.ChatbotRepository
+	session  models.ChatSession
+	linkedBy string // Resume a concurrent save links before ours, if any
+}
+
+func (r *linkingRepo) GetSession(ctx context.Context, userID, sessionID string) (models.ChatSession, error) {
+	return r.session, nil
+}
+
+func (r *linkingRepo) LinkSessionResume(ctx context.Context, userID, sessionID, resumeID string) error {
+	if r.linkedBy != "" {
+		r.session.ResumeID = r.linkedBy
+		return models.ErrSessionResumeLinked
+	}
+	r.session.ResumeID = resumeID
+	return nil
+}
+
+func TestSaveSessionResume(t *testing.T) {
+	utils.InitLoggers()
+	const userID = "user-1"
+
+	tests := []struct {
+		name        string
+		linked      bool // Whether the session already has a resume
+		concurrent  bool // Whether a concurrent save links a resume first
+		wantCreated bool
+	}{
+		{name: "first save creates a resume", wantCreated: true},
+		{name: "later saves update the linked resume", linked: true},
+		{name: "losing a concurrent first save updates the winner's resume", concurrent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resumes := models.NewInMemoryResumeRepository()
+			existing, err := resumes.Create(userID, models.Resume{ID: "existing", Summary: "Old"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			repo := &linkingRepo{session: models.ChatSession{ID: "session-1", UserID: userID}}
+			if tt.linked {
+				repo.session.ResumeID = existing.ID
+			}
+			if tt.concurrent {
+				repo.linkedBy = existing.ID
+			}
+			controller := NewChatbotController(repo, resumes)
+
+			saved, created, err := controller.saveSessionResume(context.Background(), userID, repo.session, models.Resume{Summary: "New"})
+			if err != nil {
+				t.Fatalf("saveSessionResume() error = %v", err)
+			}
+			if created != tt.wantCreated {
+				t.Errorf("saveSessionResume() created = %v, want %v", created, tt.wantCreated)
+			}
+			if saved.Summary != "New" || saved.ID != repo.session.ResumeID {
+				t.Errorf("saveSessionResume() = %s %q, want the session's resume %s with the new summary", saved.ID, saved.Summary, repo.session.ResumeID)
+			}
+
+			all := resumes.FindAll(userID)
+			want := 1
+			if tt.wantCreated {
+				want = 2
+			}
+			if len(all) != want {
+				t.Errorf("user has %d resumes, want %d", len(all), want)
+			}
+		})
+	}
+}
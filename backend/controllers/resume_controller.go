@@ -1,22 +1,117 @@
 package controllers
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"resume.in/backend/models"
+	"resume.in/backend/render"
+	"resume.in/backend/utils"
 )
 
+
+// maxResumeImportSize limits the size of uploaded resume files
+const maxResumeImportSize = 10 << 20
+
 // ResumeController handles resume-related HTTP requests
 type ResumeController struct {
 	repository models.ResumeRepository
+	extractor  *models.ResumeExtractor // nil when no LLM is configured
 }
 
-// NewResumeController creates a new instance of ResumeController
-func NewResumeController(repository models.ResumeRepository) *ResumeController {
+// NewResumeController creates a new instance of ResumeController. Without an
+// extractor, imported files are parsed with heuristics only.
+func NewResumeController(repository models.ResumeRepository, extractor *models.ResumeExtractor) *ResumeController {
 	return &ResumeController{
 		repository: repository,
+		extractor:  extractor,
+	}
+}
+
+// getUserID returns the authenticated user's ID set by the auth middleware
+func getUserID(ctx *gin.Context) (string, bool) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		return "", false
+	}
+	id, ok := userID.(string)
+	return id, ok && id != ""
+}
+
+// requireUserID returns the authenticated user's ID, or responds with 401
+func requireUserID(ctx *gin.Context) (string, bool) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	}
+	return userID, ok
+}
+
+// resumeErrorStatus maps a repository error to an HTTP status code
+func resumeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrResumeForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrResumeNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// serveResume exports the resume in the chosen format (PDF when empty) and sends it
+// as a download. For text formats templateName names one of the user's custom
+// templates, which templates looks up. The file is built in memory so the
+// response can carry its exact length.
+func serveResume(ctx *gin.Context, templates models.ResumeRepository, userID string, resume models.Resume, formatName, templateName string, opts render.Options) {
+	format, err := render.LookupFormat(formatName)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
+
+	var buf bytes.Buffer
+	if format.Custom && templateName != "" {
+		var custom models.CustomTemplate
+		custom, err = templates.FindTemplate(userID, format.Name, templateName)
+		if err != nil {
+			ctx.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		err = format.WriteCustom(ctx.Request.Context(), &buf, resume, custom.Content)
+	} else {
+		err = format.Write(&buf, resume, templateName, opts)
+	}
+	if err != nil {
+		if errors.Is(err, render.ErrUnknownTemplate) || errors.Is(err, render.ErrInvalidOptions) || errors.Is(err, render.ErrInvalidTemplate) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		utils.Error("Failed to generate %s: %v", format.Name, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate " + strings.ToUpper(format.Name)})
+		return
+	}
+
+	utils.Info("Resume %s generated successfully (%d bytes)", format.Name, buf.Len())
+
+	sendAttachment(ctx, render.Filename(resume, format.Name), format.ContentType, buf.Bytes())
+}
+
+// sendAttachment sends data as a file download
+func sendAttachment(ctx *gin.Context, filename, contentType string, data []byte) {
+	// FormatMediaType switches to the RFC 2231 encoding for names that aren't plain ASCII
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	ctx.Header("Content-Length", strconv.Itoa(len(data)))
+	ctx.Data(http.StatusOK, contentType, data)
 }
 
 // GetResume retrieves a resume by ID
@@ -29,23 +124,28 @@ func NewResumeController(repository models.ResumeRepository) *ResumeController {
 // @Param id path string true "Resume ID"
 // @Success 200 {object} models.Resume
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Resume belongs to another user"
 // @Failure 404 {object} map[string]interface{} "Resume not found"
 // @Router /resumes/{id} [get]
 func (c *ResumeController) GetResume(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
 	id := ctx.Param("id")
 	
-	resume, err := c.repository.FindByID(id)
+	resume, err := c.repository.FindByID(userID, id)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		ctx.JSON(resumeErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	
 	ctx.JSON(http.StatusOK, resume)
 }
 
-// GetResumes retrieves all resumes
+// GetResumes retrieves the current user's resumes
 // @Summary Get all resumes
-// @Description Get a list of all available resumes
+// @Description Get a list of the resumes owned by the current user
 // @Tags resume
 // @Accept json
 // @Produce json
@@ -54,13 +154,21 @@ func (c *ResumeController) GetResume(ctx *gin.Context) {
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Router /resumes [get]
 func (c *ResumeController) GetResumes(ctx *gin.Context) {
-	resumes := c.repository.FindAll()
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+	
+	resumes := c.repository.FindAll(userID)
+	if resumes == nil {
+		resumes = []models.Resume{}
+	}
 	ctx.JSON(http.StatusOK, resumes)
 }
 
 // CreateResume adds a new resume
 // @Summary Create a new resume
-// @Description Add a new resume to the system
+// @Description Add a new resume owned by the current user. The server assigns the ID; any ID in the request is ignored.
 // @Tags resume
 // @Accept json
 // @Produce json
@@ -69,8 +177,14 @@ func (c *ResumeController) GetResumes(ctx *gin.Context) {
 // @Success 201 {object} models.Resume
 // @Failure 400 {object} map[string]interface{} "Invalid request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /resumes [post]
 func (c *ResumeController) CreateResume(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+	
 	var resume models.Resume
 	
 	if err := ctx.ShouldBindJSON(&resume); err != nil {
@@ -78,20 +192,395 @@ func (c *ResumeController) CreateResume(ctx *gin.Context) {
 		return
 	}
 	
-	// For demo, we'll use the name as ID if ID is not provided
-	if resume.ID == "" && resume.BasicInfo.Name != "" {
-		resume.ID = resume.BasicInfo.Name
-	}
+	// The server always assigns the ID. Accepting one from the client would
+	// tell it whether another user's resume has that ID.
+	resume.ID = utils.GenerateUUID()
 	
-	createdResume, err := c.repository.Create(resume)
+	createdResume, err := c.repository.Create(userID, resume)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.Error("Failed to create resume: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save resume"})
 		return
 	}
 	
 	ctx.JSON(http.StatusCreated, createdResume)
 }
 
+// ExportResume renders a stored resume as a PDF or Word document
+// @Summary Download a resume
+// @Description Render a stored resume as a PDF using one of the templates from /templates, as a Word document with format=docx, or as Markdown, HTML or plain text with format md, html or txt. /resumes/{id}/pdf is an alias of this endpoint.
+// @Tags resume
+// @Produce application/pdf
+// @Produce application/vnd.openxmlformats-officedocument.wordprocessingml.document
+// @Produce text/markdown
+// @Produce text/html
+// @Produce text/plain
+// @Security Bearer
+// @Param id path string true "Resume ID"
+// @Param format query string false "File format: pdf (default), docx, md, html or txt"
+// @Param template query string false "Template name: a PDF template (default classic), or one of your custom templates for md, html and txt"
+// @Param paper query string false "Paper size: A4 or Letter"
+// @Param font query string false "Font family, such as Liberation Sans"
+// @Param accent query string false "Accent colour as #RRGGBB"
+// @Success 200 {file} binary "Resume file"
+// @Failure 400 {object} map[string]interface{} "Unknown format, unknown template, invalid options or a custom template that fails to run"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Resume belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Resume or custom template not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /resumes/{id}/export [get]
+func (c *ResumeController) ExportResume(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+	id := ctx.Param("id")
+	
+	resume, err := c.repository.FindByID(userID, id)
+	if err != nil {
+		ctx.JSON(resumeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	
+	serveResume(ctx, c.repository, userID, resume, ctx.Query("format"), ctx.Query("template"), render.Options{
+		Paper:  ctx.Query("paper"),
+		Font:   ctx.Query("font"),
+		Accent: ctx.Query("accent"),
+	})
+}
+
+// ImportJSONResume creates a resume from a JSON Resume document
+// @Summary Import a JSON Resume
+// @Description Create a resume from a JSON Resume (jsonresume.org) document. Fields and sections Resume.in has no place for are left out and listed in dropped.
+// @Tags resume
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param resume body models.JSONResume true "JSON Resume document"
+// @Success 201 {object} map[string]interface{} "resume and dropped"
+// @Failure 400 {object} map[string]interface{} "Invalid JSON Resume"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /resumes/import/jsonresume [post]
+func (c *ResumeController) ImportJSONResume(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := ctx.GetRawData()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	resume, dropped, err := models.ParseJSONResume(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resume.ID = utils.GenerateUUID()
+	created, err := c.repository.Create(userID, resume)
+	if err != nil {
+		utils.Error("Failed to import JSON Resume: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save resume"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"resume":  created,
+		"dropped": dropped,
+	})
+}
+
+// ImportResume reads a resume from an uploaded PDF and returns it as a draft
+// @Summary Import a PDF resume
+// @Description Extract the text of a PDF resume and map it to a resume draft, using the LLM when one is configured and heuristics otherwise. The draft is not saved: it carries a fresh ID so that, once reviewed, it can be sent to POST /resumes as is. The extracted text is returned alongside for comparison. Scanned PDFs without a text layer cannot be imported.
+// @Tags resume
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param file formData file true "PDF resume, at most 10 MB"
+// @Param parser formData string false "auto (default), llm or heuristic"
+// @Success 200 {object} map[string]interface{} "resume, parser and text"
+// @Failure 400 {object} map[string]interface{} "Invalid request or unreadable PDF"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 422 {object} map[string]interface{} "The PDF has no extractable text"
+// @Failure 502 {object} map[string]interface{} "The LLM could not extract the resume"
+// @Failure 503 {object} map[string]interface{} "The llm parser was requested but no LLM is configured"
+// @Router /resumes/import [post]
+func (c *ResumeController) ImportResume(ctx *gin.Context) {
+	if _, ok := requireUserID(ctx); !ok {
+		return
+	}
+
+	parser := strings.ToLower(strings.TrimSpace(ctx.PostForm("parser")))
+	switch parser {
+	case "":
+		parser = "auto"
+	case "auto", "heuristic":
+	case "llm":
+		if c.extractor == nil {
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "No LLM is configured, use the heuristic parser"})
+			return
+		}
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Parser must be auto, llm or heuristic"})
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "A PDF file is required in the file field"})
+		return
+	}
+	if fileHeader.Size > maxResumeImportSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Resume files are limited to %d MB", maxResumeImportSize>>20)})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read resume file"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxResumeImportSize+1))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read resume file"})
+		return
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Only PDF resumes can be imported"})
+		return
+	}
+
+	text, err := utils.PDFText(data)
+	if errors.Is(err, utils.ErrNoPDFText) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "The PDF has no text to import. Scanned resumes are not supported."})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var resume models.Resume
+	if parser != "heuristic" && c.extractor != nil {
+		resume, err = c.extractor.ExtractDocument(ctx.Request.Context(), text)
+		if err == nil {
+			parser = "llm"
+		} else if parser == "llm" {
+			utils.Error("Failed to extract imported resume: %v", err)
+			ctx.JSON(http.StatusBadGateway, gin.H{"error": "Failed to extract the resume with the LLM"})
+			return
+		} else {
+			utils.Warning("LLM extraction of imported resume failed, using heuristics: %v", err)
+		}
+	}
+	if parser != "llm" {
+		resume = models.ParseResumeText(text)
+		parser = "heuristic"
+	}
+
+	resume.ID = utils.GenerateUUID()
+	ctx.JSON(http.StatusOK, gin.H{
+		"resume": resume,
+		"parser": parser,
+		"text":   text,
+	})
+}
+
+// ExportJSONResume converts a stored resume to a JSON Resume document
+// @Summary Export as JSON Resume
+// @Description Get a stored resume as a JSON Resume (jsonresume.org) document for use with JSON Resume themes and tools. Resume fields the schema has no place for are left out and listed, comma separated, in the X-Dropped-Fields header.
+// @Tags resume
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Resume ID"
+// @Success 200 {object} models.JSONResume
+// @Header 200 {string} X-Dropped-Fields "Resume fields left out of the document"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Resume belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Resume not found"
+// @Router /resumes/{id}/export/jsonresume [get]
+func (c *ResumeController) ExportJSONResume(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	resume, err := c.repository.FindByID(userID, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(resumeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	doc, dropped := resume.ToJSONResume()
+	if len(dropped) > 0 {
+		ctx.Header("X-Dropped-Fields", strings.Join(dropped, ", "))
+	}
+	ctx.JSON(http.StatusOK, doc)
+}
+
+// ListTemplates lists the templates resumes can be rendered with
+// @Summary List resume templates
+// @Description Get the available resume templates with their default options, the supported paper sizes and fonts, and the export formats
+// @Tags resume
+// @Produce json
+// @Success 200 {object} map[string]interface{} "templates, paperSizes, fonts and formats"
+// @Router /templates [get]
+func (c *ResumeController) ListTemplates(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"templates":  render.Templates(),
+		"paperSizes": render.PaperSizes,
+		"fonts":      render.Fonts,
+		"formats":    render.Formats(),
+	})
+}
+
+// ListCustomTemplates lists the current user's custom export templates
+// @Summary List custom templates
+// @Description Get the text export templates the current user uploaded
+// @Tags resume
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} models.CustomTemplate
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /templates/custom [get]
+func (c *ResumeController) ListCustomTemplates(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.repository.FindTemplates(userID))
+}
+
+// UploadCustomTemplate stores a custom export template for the current user
+// @Summary Upload a custom template
+// @Description Upload a Go template for Markdown, HTML or plain-text exports. The template is run with the resume as its data, so it can use fields such as .BasicInfo.Name and .Experience, plus the join, joinNonEmpty, upper, dates, skill, skills and contact functions. HTML templates are escaped with html/template. Uploading a template with the same format and name replaces it.
+// @Tags resume
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param file formData file true "Template file"
+// @Param format formData string false "Export format: md, html or txt (default from the file extension)"
+// @Param name formData string false "Template name (default the file name without extensions)"
+// @Success 200 {object} models.CustomTemplate "Replaced template"
+// @Success 201 {object} models.CustomTemplate "Created template"
+// @Failure 400 {object} map[string]interface{} "Invalid request or template"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /templates/custom [post]
+func (c *ResumeController) UploadCustomTemplate(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "A template file is required in the file field"})
+		return
+	}
+	if fileHeader.Size > render.MaxCustomTemplateSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Templates are limited to %d KB", render.MaxCustomTemplateSize>>10)})
+		return
+	}
+
+	// A file named resume.md.tmpl defaults to the md format and the name "resume"
+	base := strings.TrimSuffix(filepath.Base(fileHeader.Filename), ".tmpl")
+	name := strings.TrimSpace(ctx.PostForm("name"))
+	if name == "" {
+		name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	if name == "" || len(name) > 100 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Template name must be between 1 and 100 characters"})
+		return
+	}
+
+	formatName := strings.TrimSpace(ctx.PostForm("format"))
+	if formatName == "" {
+		formatName = strings.TrimPrefix(filepath.Ext(base), ".")
+	}
+	format, err := render.LookupFormat(formatName)
+	if err != nil || !format.Custom {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Template format must be md, html or txt"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read template file"})
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(io.LimitReader(file, render.MaxCustomTemplateSize+1))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read template file"})
+		return
+	}
+	if !utf8.Valid(content) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Template must be UTF-8 text"})
+		return
+	}
+	if err := format.ParseCustom(string(content)); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, created, err := c.repository.SaveTemplate(userID, models.CustomTemplate{
+		Name:    name,
+		Format:  format.Name,
+		Content: string(content),
+	})
+	if err != nil {
+		utils.Error("Failed to save custom template: %v", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save template"})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	ctx.JSON(status, template)
+}
+
+// DeleteCustomTemplate removes one of the current user's custom templates
+// @Summary Delete a custom template
+// @Description Delete a custom export template by its ID
+// @Tags resume
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Template ID"
+// @Success 200 {object} map[string]string "status: deleted"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Template not found"
+// @Router /templates/custom/{id} [delete]
+func (c *ResumeController) DeleteCustomTemplate(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.repository.DeleteTemplate(userID, ctx.Param("id")); err != nil {
+		ctx.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// templateErrorStatus maps custom template repository errors to HTTP status codes
+func templateErrorStatus(err error) int {
+	if errors.Is(err, models.ErrTemplateNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
 // UpdateResume modifies an existing resume
 // @Summary Update a resume
 // @Description Update an existing resume by its ID
@@ -104,9 +593,14 @@ func (c *ResumeController) CreateResume(ctx *gin.Context) {
 // @Success 200 {object} models.Resume
 // @Failure 400 {object} map[string]interface{} "Invalid request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Resume belongs to another user"
 // @Failure 404 {object} map[string]interface{} "Resume not found"
 // @Router /resumes/{id} [put]
 func (c *ResumeController) UpdateResume(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
 	id := ctx.Param("id")
 	
 	var resume models.Resume
@@ -115,9 +609,9 @@ func (c *ResumeController) UpdateResume(ctx *gin.Context) {
 		return
 	}
 	
-	updatedResume, err := c.repository.Update(id, resume)
+	updatedResume, err := c.repository.Update(userID, id, resume)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		ctx.JSON(resumeErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	
@@ -134,23 +628,28 @@ func (c *ResumeController) UpdateResume(ctx *gin.Context) {
 // @Param id path string true "Resume ID"
 // @Success 200 {object} map[string]string "status: deleted"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Resume belongs to another user"
 // @Failure 404 {object} map[string]interface{} "Resume not found"
 // @Router /resumes/{id} [delete]
 func (c *ResumeController) DeleteResume(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
 	id := ctx.Param("id")
 	
-	err := c.repository.Delete(id)
+	err := c.repository.Delete(userID, id)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		ctx.JSON(resumeErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	
 	ctx.JSON(http.StatusOK, gin.H{"status": "deleted"})
 }
 
-// GetAllSkills retrieves all skills from all resumes
+// GetAllSkills retrieves all skills from the current user's resumes
 // @Summary Get all skills
-// @Description Get a list of all skills from all resumes
+// @Description Get a list of all skills from the current user's resumes
 // @Tags skills
 // @Accept json
 // @Produce json
@@ -159,13 +658,18 @@ func (c *ResumeController) DeleteResume(ctx *gin.Context) {
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Router /skills [get]
 func (c *ResumeController) GetAllSkills(ctx *gin.Context) {
-	skills := c.repository.GetAllSkills()
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+	
+	skills := c.repository.GetAllSkills(userID)
 	ctx.JSON(http.StatusOK, skills)
 }
 
-// GetAllExperience retrieves all experiences from all resumes
+// GetAllExperience retrieves all experiences from the current user's resumes
 // @Summary Get all experiences
-// @Description Get a list of all work experiences from all resumes
+// @Description Get a list of all work experiences from the current user's resumes
 // @Tags experience
 // @Accept json
 // @Produce json
@@ -174,6 +678,11 @@ func (c *ResumeController) GetAllSkills(ctx *gin.Context) {
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Router /experience [get]
 func (c *ResumeController) GetAllExperience(ctx *gin.Context) {
-	experiences := c.repository.GetAllExperience()
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+	
+	experiences := c.repository.GetAllExperience(userID)
 	ctx.JSON(http.StatusOK, experiences)
 } 
\ No newline at end of file
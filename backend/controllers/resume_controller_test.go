@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"resume.in/backend/models"
+	"resume.in/backend/utils"
+)
+
+func TestResumeOwnership(t *testing.T) {
+	utils.InitLoggers()
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		user   string // Signed-in user, if any
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{name: "owner reads", user: "ada", method: http.MethodGet, path: "/resumes/ada-cv", want: http.StatusOK},
+		{name: "owner updates", user: "ada", method: http.MethodPut, path: "/resumes/ada-cv", body: `{"summary": "Changed"}`, want: http.StatusOK},
+		{name: "owner deletes", user: "ada", method: http.MethodDelete, path: "/resumes/ada-cv", want: http.StatusOK},
+		{name: "another user cannot read", user: "bob", method: http.MethodGet, path: "/resumes/ada-cv", want: http.StatusForbidden},
+		{name: "another user cannot update", user: "bob", method: http.MethodPut, path: "/resumes/ada-cv", body: `{"summary": "Changed"}`, want: http.StatusForbidden},
+		{name: "another user cannot delete", user: "bob", method: http.MethodDelete, path: "/resumes/ada-cv", want: http.StatusForbidden},
+		{name: "missing resumes are not found", user: "ada", method: http.MethodGet, path: "/resumes/nobody-cv", want: http.StatusNotFound},
+		{name: "signed-out requests are unauthorized", method: http.MethodGet, path: "/resumes/ada-cv", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := models.NewInMemoryResumeRepository()
+			if _, err := repo.Create("ada", models.Resume{ID: "ada-cv", Summary: "Original"}); err != nil {
+				t.Fatal(err)
+			}
+			controller := NewResumeController(repo, nil)
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if user := c.GetHeader("X-Test-User"); user != "" {
+					c.Set("userID", user)
+				}
+			})
+			router.GET("/resumes/:id", controller.GetResume)
+			router.PUT("/resumes/:id", controller.UpdateResume)
+			router.DELETE("/resumes/:id", controller.DeleteResume)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Test-User", tt.user)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("%s %s as %q = %d, want %d: %s", tt.method, tt.path, tt.user, w.Code, tt.want, w.Body)
+			}
+
+			// Requests that were refused leave the owner's resume as it was
+			if tt.want != http.StatusOK {
+				resume, err := repo.FindByID("ada", "ada-cv")
+				if err != nil || resume.Summary != "Original" {
+					t.Errorf("owner's resume = %+v, %v, want it unchanged", resume, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGetResumesListsOwnResumes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := models.NewInMemoryResumeRepository()
+	for id, owner := range map[string]string{"ada-cv": "ada", "ada-letter": "ada", "bob-cv": "bob"} {
+		if _, err := repo.Create(owner, models.Resume{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	controller := NewResumeController(repo, nil)
+
+	tests := []struct {
+		user string
+		want int
+	}{
+		{"ada", 2},
+		{"bob", 1},
+		{"carol", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/resumes", nil)
+			c.Set("userID", tt.user)
+			controller.GetResumes(c)
+
+			var resumes []models.Resume
+			if err := json.Unmarshal(w.Body.Bytes(), &resumes); err != nil {
+				t.Fatalf("GetResumes() body %s: %v", w.Body, err)
+			}
+			if len(resumes) != tt.want {
+				t.Fatalf("GetResumes() = %d resumes, want %d", len(resumes), tt.want)
+			}
+			for _, resume := range resumes {
+				if resume.OwnerID != tt.user {
+					t.Errorf("GetResumes() returned %s owned by %s", resume.ID, resume.OwnerID)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateResumeAssignsID(t *testing.T) {
+	utils.InitLoggers()
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"without an ID", `{"summary": "New"}`},
+		{"with a new ID", `{"id": "bob-cv", "summary": "New"}`},
+		{"with another user's ID", `{"id": "ada-cv", "summary": "New"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := models.NewInMemoryResumeRepository()
+			if _, err := repo.Create("ada", models.Resume{ID: "ada-cv", Summary: "Original"}); err != nil {
+				t.Fatal(err)
+			}
+			controller := NewResumeController(repo, nil)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/resumes", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("userID", "bob")
+			controller.CreateResume(c)
+
+			if w.Code != http.StatusCreated {
+				t.Fatalf("CreateResume() = %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+			}
+			var created models.Resume
+			if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+				t.Fatal(err)
+			}
+			if created.ID == "" || created.ID == "ada-cv" || created.ID == "bob-cv" || created.OwnerID != "bob" {
+				t.Errorf("CreateResume() = %s owned by %s, want a new ID owned by bob", created.ID, created.OwnerID)
+			}
+			if resume, err := repo.FindByID("ada", "ada-cv"); err != nil || resume.Summary != "Original" {
+				t.Errorf("other user's resume = %+v, %v, want it unchanged", resume, err)
+			}
+		})
+	}
+}
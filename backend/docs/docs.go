@@ -1,4 +1,5 @@
-// Package docs Code generated by swaggo/swag. DO NOT EDIT
+// Code generated by swaggo/swag. DO NOT EDIT.
+
 package docs
 
 import "github.com/swaggo/swag"
@@ -26,7 +27,7 @@ const docTemplate = `{
     "paths": {
         "/auth/google/callback": {
             "get": {
-                "description": "Processes the OAuth callback and returns JWT tokens",
+                "description": "Processes the OAuth callback and returns JWT tokens (handles both login and registration)",
                 "produces": [
                     "application/json"
                 ],
@@ -196,6 +197,76 @@ const docTemplate = `{
                 }
             }
         },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate a local account and return JWT tokens. The account is locked temporarily after repeated failed attempts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Log in with email and password",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or account uses Google sign-in",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid email or password",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "423": {
+                        "description": "Account temporarily locked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/auth/logout": {
             "post": {
                 "security": [
@@ -381,9 +452,10 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Upload a document to the vector store for context retrieval",
+                "description": "Upload a document to the vector store for context retrieval. Send either JSON with the text in content, or a multipart form with a PDF, DOCX, plain text or Markdown file in the file field. For files, the text is extracted and the file name, MIME type, size and uploader are added to the metadata, with the file name as the source shown in chat answers.\nDocuments only answer their uploader's chats. A session_id in the metadata limits a document to that chat session, and tags, a list of strings, let chat requests pick documents by tag.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
@@ -394,18 +466,35 @@ const docTemplate = `{
                 "summary": "Upload a document",
                 "parameters": [
                     {
-                        "description": "Document upload request",
+                        "description": "Document upload request (JSON)",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
                             "$ref": "#/definitions/controllers.UploadDocumentRequest"
                         }
+                    },
+                    {
+                        "type": "file",
+                        "description": "PDF, DOCX, TXT or Markdown file, at most 10 MB (multipart)",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source shown in chat answers, defaults to the file name (multipart)",
+                        "name": "source",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Extra metadata as a JSON object (multipart)",
+                        "name": "metadata",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Upload status",
+                        "description": "Upload status, document_id, chunks and metadata",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -425,6 +514,27 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Session in the metadata belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Session in the metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "422": {
+                        "description": "The file has no extractable text",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -435,44 +545,62 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/generate-resume": {
-            "post": {
+        "/chat/documents": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Process chat history to generate an ATS-formatted resume in PDF",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get the documents the current user uploaded to the knowledge base, most recently uploaded first, without their chunks",
                 "produces": [
-                    "application/pdf"
+                    "application/json"
                 ],
                 "tags": [
                     "chatbot"
                 ],
-                "summary": "Generate ATS Resume",
-                "parameters": [
-                    {
-                        "description": "Generate resume request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                "summary": "List documents",
+                "responses": {
+                    "200": {
+                        "description": "Documents array",
                         "schema": {
-                            "$ref": "#/definitions/controllers.GenerateResumeRequest"
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete every knowledge base document you uploaded, with all their chunks and embeddings. Other users' documents are not affected.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chatbot"
                 ],
+                "summary": "Delete all documents",
                 "responses": {
                     "200": {
-                        "description": "Resume PDF file",
-                        "schema": {
-                            "type": "file"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request",
+                        "description": "status: deleted, and the number of deleted documents",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -495,50 +623,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/chat/history/{sessionId}": {
+        "/chat/documents/{id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get the chat history for a specific session",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get a knowledge base document with its metadata and the text of its chunks",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "chatbot"
                 ],
-                "summary": "Get chat history",
+                "summary": "Get a document",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session ID",
-                        "name": "sessionId",
+                        "description": "Document ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Chat history with session_id and messages array",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.KnowledgeDocument"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Invalid request",
+                    "403": {
+                        "description": "Document belongs to another user",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Document not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -552,18 +683,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/chat/message": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Send a message to the chatbot and get a response",
+                "description": "Replace the text and metadata of a knowledge base document with a new upload, in the same JSON or multipart form as POST /chat/document. The old chunks are swapped for the new ones at once, so chat answers never mix the two versions.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
@@ -571,21 +701,45 @@ const docTemplate = `{
                 "tags": [
                     "chatbot"
                 ],
-                "summary": "Send a message to the chatbot",
+                "summary": "Replace a document",
                 "parameters": [
                     {
-                        "description": "Chat request",
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Document upload request (JSON)",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.ChatRequest"
+                            "$ref": "#/definitions/controllers.UploadDocumentRequest"
                         }
+                    },
+                    {
+                        "type": "file",
+                        "description": "PDF, DOCX, TXT or Markdown file, at most 10 MB (multipart)",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source shown in chat answers, defaults to the file name (multipart)",
+                        "name": "source",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Extra metadata as a JSON object (multipart)",
+                        "name": "metadata",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Response with session_id, response object, and optional resume_hint",
+                        "description": "Upload status, document_id, chunks and metadata",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -605,6 +759,27 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Document, or the session in the metadata, belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Document, or the session in the metadata, not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "422": {
+                        "description": "The file has no extractable text",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -613,33 +788,37 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/experience": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get a list of all work experiences from all resumes",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Delete a knowledge base document with all its chunks and embeddings, so it is no longer used in chat answers",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "experience"
+                    "chatbot"
+                ],
+                "summary": "Delete a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all experiences",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "status: deleted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Experience"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -649,54 +828,120 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "403": {
+                        "description": "Document belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/resumes": {
-            "get": {
+        "/chat/generate-resume": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get a list of all available resumes",
+                "description": "Process chat history to generate an ATS-formatted resume in PDF, or in another format from /templates such as docx. The LLM extracts only facts the user stated; sections without evidence are left empty.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/pdf",
+                    "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
                 ],
                 "tags": [
-                    "resume"
+                    "chatbot"
+                ],
+                "summary": "Generate ATS Resume",
+                "parameters": [
+                    {
+                        "description": "Generate resume request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GenerateResumeRequest"
+                        }
+                    }
                 ],
-                "summary": "Get all resumes",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Resume file",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Resume"
+                            "type": "file"
+                        },
+                        "headers": {
+                            "X-Resume-ID": {
+                                "type": "string",
+                                "description": "ID of the saved resume when save is true"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "403": {
+                        "description": "Session belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/chat/history/{sessionId}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Add a new resume to the system",
+                "description": "Get the chat history for a specific session",
                 "consumes": [
                     "application/json"
                 ],
@@ -704,25 +949,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "resume"
+                    "chatbot"
                 ],
-                "summary": "Create a new resume",
+                "summary": "Get chat history",
                 "parameters": [
                     {
-                        "description": "Resume object",
-                        "name": "resume",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Resume"
-                        }
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Chat history with session_id and messages array",
                         "schema": {
-                            "$ref": "#/definitions/models.Resume"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -738,18 +982,39 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "403": {
+                        "description": "Session belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/resumes/{id}": {
-            "get": {
+        "/chat/message": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get a specific resume by its ID",
+                "description": "Send a message to the chatbot and get a response",
                 "consumes": [
                     "application/json"
                 ],
@@ -757,23 +1022,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "resume"
+                    "chatbot"
                 ],
-                "summary": "Get a resume by ID",
+                "summary": "Send a message to the chatbot",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Resume ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Chat request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ChatRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Response with session_id, response object, and optional resume_hint",
                         "schema": {
-                            "$ref": "#/definitions/models.Resume"
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -783,55 +1058,64 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Resume not found",
+                    "403": {
+                        "description": "Session belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "The LLM failed to answer; nothing was saved",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/chat/message/stream": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update an existing resume by its ID",
+                "description": "Send a message to the chatbot and receive the answer as server-sent events while it is generated.\nA \"session\" event carrying the session_id comes first, then one \"delta\" event per piece of the answer,\nthen a \"done\" event with the same body as /chat/message. An \"error\" event ends the stream on failure.\nThe message and its answer are saved together once the answer is complete. Closing the connection cancels generation and saves neither.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "resume"
+                    "chatbot"
                 ],
-                "summary": "Update a resume",
+                "summary": "Send a message to the chatbot and stream the answer",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Resume ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Resume object",
-                        "name": "resume",
+                        "description": "Chat request",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Resume"
+                            "$ref": "#/definitions/controllers.ChatRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Server-sent event stream",
                         "schema": {
-                            "$ref": "#/definitions/models.Resume"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -848,22 +1132,31 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Resume not found",
+                    "403": {
+                        "description": "Session belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/chat/save-resume": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete a resume by its ID",
+                "description": "Process chat history into a structured resume and save it to the current user's account.\nThe first save from a session creates a resume; later saves from the same session update it.\nThe saved resume can then be edited through /resumes/{id}.",
                 "consumes": [
                     "application/json"
                 ],
@@ -871,26 +1164,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "resume"
+                    "chatbot"
                 ],
-                "summary": "Delete a resume",
+                "summary": "Save resume from chat",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Resume ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Save resume request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GenerateResumeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "status: deleted",
+                        "description": "Updated resume with resume_id and resume",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
+                        }
+                    },
+                    "201": {
+                        "description": "Created resume with resume_id and resume",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -900,8 +1207,22 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Session belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
-                        "description": "Resume not found",
+                        "description": "Session not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -910,32 +1231,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/skills": {
+        "/chat/sessions": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get a list of all skills from all resumes",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get the current user's chat sessions, most recently active first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "skills"
+                    "chatbot"
                 ],
-                "summary": "Get all skills",
+                "summary": "List chat sessions",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Sessions array",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Skill"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
@@ -944,172 +1260,1406 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "controllers.ChatRequest": {
-            "type": "object",
-            "required": [
-                "query"
-            ],
-            "properties": {
-                "query": {
+        },
+        "/experience": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a list of all work experiences from the current user's resumes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "experience"
+                ],
+                "summary": "Get all experiences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Experience"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/resumes": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a list of the resumes owned by the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resume"
+                ],
+                "summary": "Get all resumes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Resume"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Add a new resume owned by the current user. The server assigns the ID; any ID in the request is ignored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resume"
+                ],
+                "summary": "Create a new resume",
+                "parameters": [
+                    {
+                        "description": "Resume object",
+                        "name": "resume",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Resume"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Resume"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/resumes/import": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Extract the text of a PDF resume and map it to a resume draft, using the LLM when one is configured and heuristics otherwise. The draft is not saved: it carries a fresh ID so that, once reviewed, it can be sent to POST /resumes as is. The extracted text is returned alongside for comparison. Scanned PDFs without a text layer cannot be imported.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resume"
+                ],
+                "summary": "Import a PDF resume",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "PDF resume, at most 10 MB",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "auto (default), llm or heuristic",
+                        "name": "parser",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "resume, parser and text",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or unreadable PDF",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "422": {
+                        "description": "The PDF has no extractable text",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "The LLM could not extract the resume",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "The llm parser was requested but no LLM is configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/resumes/import/jsonresume": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a resume from a JSON Resume (jsonresume.org) document. Fields and sections Resume.in has no place for are left out and listed in dropped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resume"
+                ],
+                "summary": "Import a JSON Resume",
+                "parameters": [
+                    {
+                        "description": "JSON Resume document",
+                        "name": "resume",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.JSONResume"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "resume and dropped",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON Resume",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/resumes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a specific resume by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resume"
+                ],
+                "summary": "Get a resume by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resume ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Resume"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Resume belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Resume not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update an existing resume by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resume"
+                ],
+                "summary": "Update a resume",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resume ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Resume object",
+                        "name": "resume",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Resume"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Resume"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Resume belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Resume not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a resume by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resume"
+                ],
+                "summary": "Delete a resume",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resume ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "status: deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Resume belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Resume not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/resumes/{id}/export": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Render a stored resume as a PDF using one of the templates from /templates, as a Word document with format=docx, or as Markdown, HTML or plain text with format md, html or txt. /resumes/{id}/pdf is an alias of this endpoint.",
+                "produces": [
+                    "application/pdf",
+                    "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+                    "text/markdown",
+                    "text/html",
+                    "text/plain"
+                ],
+                "tags": [
+                    "resume"
+                ],
+                "summary": "Download a resume",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resume ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File format: pdf (default), docx, md, html or txt",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template name: a PDF template (default classic), or one of your custom templates for md, html and txt",
+                        "name": "template",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Paper size: A4 or Letter",
+                        "name": "paper",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Font family, such as Liberation Sans",
+                        "name": "font",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Accent colour as #RRGGBB",
+                        "name": "accent",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resume file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown format, unknown template, invalid options or a custom template that fails to run",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Resume belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Resume or custom template not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/resumes/{id}/export/jsonresume": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a stored resume as a JSON Resume (jsonresume.org) document for use with JSON Resume themes and tools. Resume fields the schema has no place for are left out and listed, comma separated, in the X-Dropped-Fields header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resume"
+                ],
+                "summary": "Export as JSON Resume",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resume ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.JSONResume"
+                        },
+                        "headers": {
+                            "X-Dropped-Fields": {
+                                "type": "string",
+                                "description": "Resume fields left out of the document"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Resume belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Resume not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/skills": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a list of all skills from the current user's resumes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skills"
+                ],
+                "summary": "Get all skills",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Skill"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/templates": {
+            "get": {
+                "description": "Get the available resume templates with their default options, the supported paper sizes and fonts, and the export formats",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resume"
+                ],
+                "summary": "List resume templates",
+                "responses": {
+                    "200": {
+                        "description": "templates, paperSizes, fonts and formats",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/custom": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the text export templates the current user uploaded",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resume"
+                ],
+                "summary": "List custom templates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CustomTemplate"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Upload a Go template for Markdown, HTML or plain-text exports. The template is run with the resume as its data, so it can use fields such as .BasicInfo.Name and .Experience, plus the join, joinNonEmpty, upper, dates, skill, skills and contact functions. HTML templates are escaped with html/template. Uploading a template with the same format and name replaces it.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resume"
+                ],
+                "summary": "Upload a custom template",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Template file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Export format: md, html or txt (default from the file extension)",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template name (default the file name without extensions)",
+                        "name": "name",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Replaced template",
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomTemplate"
+                        }
+                    },
+                    "201": {
+                        "description": "Created template",
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or template",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/custom/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a custom export template by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "resume"
+                ],
+                "summary": "Delete a custom template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "status: deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.ChatRequest": {
+            "type": "object",
+            "required": [
+                "query"
+            ],
+            "properties": {
+                "query": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Only answer from documents with one of these tags",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "controllers.GenerateResumeRequest": {
+            "type": "object",
+            "required": [
+                "session_id"
+            ],
+            "properties": {
+                "accent": {
+                    "description": "Accent colour as #RRGGBB",
+                    "type": "string"
+                },
+                "font": {
+                    "description": "Font family, one of Fonts or an alias",
+                    "type": "string"
+                },
+                "format": {
+                    "description": "pdf (default), docx, md, html or txt",
+                    "type": "string"
+                },
+                "paper": {
+                    "description": "A4 or Letter",
+                    "type": "string"
+                },
+                "query": {
+                    "description": "Make query optional for compatibility with chat requests",
+                    "type": "string"
+                },
+                "save": {
+                    "description": "Also save the resume to the user's account",
+                    "type": "boolean"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "template": {
+                    "description": "PDF template from /templates, or a custom template for text formats",
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                }
+            }
+        },
+        "controllers.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "controllers.RegistrationRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8,
+                    "example": "password123"
+                }
+            }
+        },
+        "controllers.UploadDocumentRequest": {
+            "type": "object",
+            "required": [
+                "content"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "models.BasicInfo": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "github": {
+                    "type": "string"
+                },
+                "linkedin": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "website": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Certificate": {
+            "type": "object",
+            "properties": {
+                "expiryDate": {
+                    "type": "string"
+                },
+                "issueDate": {
+                    "type": "string"
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CustomTemplate": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "Go template source",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "format": {
+                    "description": "Export format the template renders, such as md, html or txt",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ownerId": {
+                    "description": "ID of the user who uploaded the template",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DocumentChunk": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "description": "Character offset in the document text",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Education": {
+            "type": "object",
+            "properties": {
+                "degree": {
                     "type": "string"
                 },
-                "session_id": {
+                "endDate": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "gpa": {
+                    "type": "string"
+                },
+                "institution": {
+                    "type": "string"
+                },
+                "startDate": {
                     "type": "string"
                 }
             }
         },
-        "controllers.GenerateResumeRequest": {
+        "models.Experience": {
             "type": "object",
-            "required": [
-                "session_id"
-            ],
             "properties": {
-                "query": {
-                    "description": "Make query optional for compatibility with chat requests",
+                "company": {
                     "type": "string"
                 },
-                "session_id": {
+                "description": {
+                    "type": "string"
+                },
+                "endDate": {
+                    "type": "string"
+                },
+                "highlights": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "position": {
+                    "type": "string"
+                },
+                "startDate": {
                     "type": "string"
                 }
             }
         },
-        "controllers.LoginResponse": {
+        "models.JSONResume": {
             "type": "object",
             "properties": {
-                "expires_in": {
-                    "type": "integer"
-                },
-                "refresh_token": {
+                "$schema": {
                     "type": "string"
                 },
-                "token": {
-                    "type": "string"
+                "awards": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
                 },
-                "user": {
-                    "$ref": "#/definitions/models.User"
+                "basics": {
+                    "$ref": "#/definitions/models.JSONResumeBasics"
+                },
+                "certificates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JSONResumeCertificate"
+                    }
+                },
+                "education": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JSONResumeEducation"
+                    }
+                },
+                "interests": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "meta": {
+                    "type": "object"
+                },
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JSONResumeProject"
+                    }
+                },
+                "publications": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "references": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JSONResumeSkill"
+                    }
+                },
+                "volunteer": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "work": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JSONResumeWork"
+                    }
                 }
             }
         },
-        "controllers.RegistrationRequest": {
+        "models.JSONResumeBasics": {
             "type": "object",
-            "required": [
-                "email",
-                "name",
-                "password"
-            ],
             "properties": {
                 "email": {
-                    "type": "string",
-                    "example": "user@example.com"
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "location": {
+                    "$ref": "#/definitions/models.JSONResumeLocation"
                 },
                 "name": {
-                    "type": "string",
-                    "example": "John Doe"
+                    "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8,
-                    "example": "password123"
+                "phone": {
+                    "type": "string"
+                },
+                "profiles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JSONResumeProfile"
+                    }
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.UploadDocumentRequest": {
+        "models.JSONResumeCertificate": {
             "type": "object",
-            "required": [
-                "content"
-            ],
             "properties": {
-                "content": {
+                "date": {
                     "type": "string"
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": true
+                "issuer": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "models.BasicInfo": {
+        "models.JSONResumeEducation": {
             "type": "object",
             "properties": {
-                "address": {
+                "area": {
                     "type": "string"
                 },
-                "email": {
+                "courses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "endDate": {
                     "type": "string"
                 },
-                "github": {
+                "institution": {
                     "type": "string"
                 },
-                "linkedin": {
+                "score": {
                     "type": "string"
                 },
-                "name": {
+                "startDate": {
                     "type": "string"
                 },
-                "phone": {
+                "studyType": {
                     "type": "string"
                 },
-                "website": {
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "models.Certificate": {
+        "models.JSONResumeLocation": {
             "type": "object",
             "properties": {
-                "expiryDate": {
+                "address": {
                     "type": "string"
                 },
-                "issueDate": {
+                "city": {
                     "type": "string"
                 },
-                "issuer": {
+                "countryCode": {
                     "type": "string"
                 },
-                "name": {
+                "postalCode": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.JSONResumeProfile": {
+            "type": "object",
+            "properties": {
+                "network": {
                     "type": "string"
                 },
                 "url": {
                     "type": "string"
+                },
+                "username": {
+                    "type": "string"
                 }
             }
         },
-        "models.Education": {
+        "models.JSONResumeProject": {
             "type": "object",
             "properties": {
-                "degree": {
+                "description": {
                     "type": "string"
                 },
                 "endDate": {
                     "type": "string"
                 },
-                "field": {
+                "entity": {
                     "type": "string"
                 },
-                "gpa": {
-                    "type": "string"
+                "highlights": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "institution": {
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
                     "type": "string"
                 },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "startDate": {
                     "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "models.Experience": {
+        "models.JSONResumeSkill": {
+            "type": "object",
+            "properties": {
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "level": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.JSONResumeWork": {
             "type": "object",
             "properties": {
                 "company": {
                     "type": "string"
                 },
                 "description": {
+                    "description": "Describes the organisation, not the role",
                     "type": "string"
                 },
                 "endDate": {
@@ -1121,11 +2671,53 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
                 "position": {
                     "type": "string"
                 },
                 "startDate": {
                     "type": "string"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.KnowledgeDocument": {
+            "type": "object",
+            "properties": {
+                "chunk_count": {
+                    "type": "integer"
+                },
+                "chunks": {
+                    "description": "Only set for a single document",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DocumentChunk"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "description": "Metadata given on upload, without the chunk keys",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "uploaded_at": {
+                    "description": "When the current version was uploaded",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
@@ -1182,6 +2774,10 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "ownerId": {
+                    "description": "ID of the user who owns the resume",
+                    "type": "string"
+                },
                 "projects": {
                     "type": "array",
                     "items": {
@@ -47,6 +47,10 @@ func main() {
 
 	// Load configuration
 	cfg := config.LoadConfigFromEnv()
+	if err := cfg.Validate(); err != nil {
+		utils.Error("Invalid configuration: %v", err)
+		os.Exit(1)
+	}
 
 	// Set Gin mode based on environment
 	if cfg.Environment == "production" {
@@ -57,6 +61,7 @@ func main() {
 	var resumeRepo models.ResumeRepository
 	var chatbotRepo models.ChatbotRepository
 	var userRepo models.UserRepository
+	var resumeExtractor *models.ResumeExtractor
 	var maxRetries = 5
 	var retryDelay = 5 * time.Second
 
@@ -90,22 +95,40 @@ func main() {
 			os.Exit(1)
 		}
 
-		// Initialize sample data
-		if err := postgresRepo.InitDemoData(); err != nil {
-			utils.Error("Failed to initialize demo data: %v", err)
-		}
-
 		resumeRepo = postgresRepo
 
-		// Setup PostgreSQL repository for chatbot
-		// Use simplified implementation to avoid LangChain dependency issues
-		repo, err := models.NewPostgresChatbotRepository(db)
+		// Setup the embedder used for document retrieval
+		embedder, err := models.NewEmbedder(cfg)
+		if err != nil {
+			utils.Error("Failed to initialize embedder: %v. Falling back to the offline hash embedder", err)
+			embedder = models.NewHashEmbedder(cfg.EmbeddingDimensions)
+		}
+
+		// Setup the language model client
+		llm, err := models.NewLLMClient(cfg)
 		if err != nil {
-			utils.Error("Failed to initialize chatbot repository: %v", err)
+			utils.Error("Failed to initialize LLM client: %v", err)
 			// Continue with other features
 		} else {
-			chatbotRepo = repo
-			utils.Info("Chatbot repository initialized")
+			resumeExtractor = models.NewResumeExtractor(llm)
+
+			var reranker *models.Reranker
+			if cfg.RerankDocuments {
+				reranker = models.NewReranker(llm)
+			}
+			summarizer := models.NewSummarizer(llm, cfg.SummarizeAfter, cfg.KeepUnsummarized)
+			contextBuilder := models.NewContextBuilder(cfg.LLMModel, cfg.LLMContextWindow, cfg.LLMMaxTokens)
+
+			// Setup PostgreSQL repository for chatbot
+			// Use simplified implementation to avoid LangChain dependency issues
+			repo, err := models.NewPostgresChatbotRepository(db, embedder, llm, models.NewChunker(cfg.ChunkSize, cfg.ChunkOverlap), reranker, summarizer, contextBuilder)
+			if err != nil {
+				utils.Error("Failed to initialize chatbot repository: %v", err)
+				// Continue with other features
+			} else {
+				chatbotRepo = repo
+				utils.Info("Chatbot repository initialized")
+			}
 		}
 
 		// Setup PostgreSQL repository for users
@@ -120,9 +143,7 @@ func main() {
 		utils.Info("Falling back to in-memory repository")
 		
 		// Initialize repository
-		memoryRepo := models.NewInMemoryResumeRepository()
-		memoryRepo.InitDemoData()
-		resumeRepo = memoryRepo
+		resumeRepo = models.NewInMemoryResumeRepository()
 		
 		// For users, we need a database connection
 		utils.Error("Authentication features require a database connection")
@@ -131,7 +152,7 @@ func main() {
 
 	// Initialize controllers
 	authController := controllers.NewAuthController(cfg, userRepo)
-	resumeController := controllers.NewResumeController(resumeRepo)
+	resumeController := controllers.NewResumeController(resumeRepo, resumeExtractor)
 	
 	// Initialize chatbot controller if repository is available
 	var chatbotController *controllers.ChatbotController
@@ -0,0 +1,6 @@
+-- Track failed password logins so local accounts can be locked temporarily
+ALTER TABLE users
+ADD COLUMN failed_login_attempts INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE users
+ADD COLUMN locked_until TIMESTAMP;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_resumes_owner_id;
+
+ALTER TABLE resumes
+DROP COLUMN IF EXISTS owner_id;
@@ -0,0 +1,19 @@
+-- The resumes table used to be created by the repository at startup, so it
+-- may not exist yet on a fresh database
+CREATE TABLE IF NOT EXISTS resumes (
+    id VARCHAR(100) PRIMARY KEY,
+    data JSONB NOT NULL
+);
+
+ALTER TABLE resumes
+ADD COLUMN IF NOT EXISTS owner_id VARCHAR(255) REFERENCES users(id) ON DELETE CASCADE;
+
+-- Backfill owners by matching the resume's contact email to a user account.
+-- Resumes that match no user stay unowned and are hidden from every user.
+UPDATE resumes r
+SET owner_id = u.id
+FROM users u
+WHERE r.owner_id IS NULL
+  AND LOWER(u.email) = LOWER(r.data->'basicInfo'->>'email');
+
+CREATE INDEX IF NOT EXISTS idx_resumes_owner_id ON resumes(owner_id);
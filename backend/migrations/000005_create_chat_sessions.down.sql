@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_chat_sessions_user_id;
+DROP TABLE IF EXISTS chat_sessions;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS chat_sessions (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) REFERENCES users(id) ON DELETE CASCADE,
+    title TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_sessions_user_id ON chat_sessions(user_id, updated_at DESC);
+
+-- Register sessions that already have messages. Their owner is unknown, so
+-- they stay unowned and nobody can read or continue them.
+DO $$
+BEGIN
+    IF to_regclass('chat_messages') IS NOT NULL THEN
+        INSERT INTO chat_sessions (id, user_id, title, created_at, updated_at)
+        SELECT session_id, NULL, '', MIN(created_at), MAX(created_at)
+        FROM chat_messages
+        GROUP BY session_id
+        ON CONFLICT (id) DO NOTHING;
+    END IF;
+END $$;
@@ -0,0 +1,2 @@
+ALTER TABLE chat_sessions
+DROP COLUMN IF EXISTS resume_id;
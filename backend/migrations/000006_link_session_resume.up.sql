@@ -0,0 +1,4 @@
+-- Remember which resume was saved from each chat session so regenerating
+-- updates it instead of creating a duplicate
+ALTER TABLE chat_sessions
+ADD COLUMN IF NOT EXISTS resume_id VARCHAR(100) REFERENCES resumes(id) ON DELETE SET NULL;
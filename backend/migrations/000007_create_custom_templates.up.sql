@@ -0,0 +1,12 @@
+-- Text export templates uploaded by users. Each user can keep one template
+-- per export format and name.
+CREATE TABLE IF NOT EXISTS custom_templates (
+    id VARCHAR(100) PRIMARY KEY,
+    owner_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    format VARCHAR(20) NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (owner_id, format, name)
+);
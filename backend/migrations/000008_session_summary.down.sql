@@ -0,0 +1,3 @@
+ALTER TABLE chat_sessions
+DROP COLUMN IF EXISTS summary_message_id,
+DROP COLUMN IF EXISTS summary;
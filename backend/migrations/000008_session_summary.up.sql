@@ -0,0 +1,5 @@
+-- The rolling summary of older messages in long chat sessions
+-- summary_message_id is the last message the summary covers
+ALTER TABLE chat_sessions
+ADD COLUMN IF NOT EXISTS summary TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS summary_message_id INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- Lock expiries are instants, so store them with their time zone. The old
+-- column held the server's wall-clock time, which is UTC in the Docker setup.
+ALTER TABLE users
+ALTER COLUMN locked_until TYPE TIMESTAMPTZ USING locked_until AT TIME ZONE 'UTC';
@@ -2,9 +2,40 @@ package models
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+var (
+	// ErrSessionNotFound is returned when no chat session exists with the given ID
+	ErrSessionNotFound = errors.New("chat session not found")
+	// ErrSessionForbidden is returned when a chat session belongs to another user
+	ErrSessionForbidden = errors.New("chat session belongs to another user")
+	// ErrSessionResumeLinked is returned when a chat session already has a resume linked to it
+	ErrSessionResumeLinked = errors.New("chat session already has a resume")
+	// ErrDocumentNotFound is returned when no knowledge base document exists with the given ID
+	ErrDocumentNotFound = errors.New("document not found")
+	// ErrDocumentForbidden is returned when a knowledge base document belongs to another user
+	ErrDocumentForbidden = errors.New("document belongs to another user")
+	// ErrDocumentEmpty is returned when a document has no text to store
+	ErrDocumentEmpty = errors.New("document has no text")
+	// ErrLLMUnavailable is returned when the LLM fails to answer a chat query at all
+	ErrLLMUnavailable = errors.New("the assistant is unavailable, please try again later")
+	// ErrDocumentMetadata is returned when a document's session_id or tags metadata is malformed
+	ErrDocumentMetadata = errors.New("metadata session_id must be a string and tags a list of strings")
+)
+
+// ChatSession represents a conversation owned by a user
+type ChatSession struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Title     string    `json:"title"`
+	ResumeID  string    `json:"resume_id,omitempty"` // Resume saved from this session, if any
+	Summary   string    `json:"summary,omitempty"`   // Summary of the older messages, kept up to date as the conversation grows
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 // ChatMessage represents a message in a chat conversation
 type ChatMessage struct {
 	ID        int64     `json:"id"`
@@ -23,10 +54,11 @@ type ChatQuery struct {
 
 // ChatResponse represents a response from the LLM
 type ChatResponse struct {
-	Answer    string    `json:"answer"`
-	Sources   []string  `json:"sources,omitempty"`
-	Metadata  any       `json:"metadata,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
+	Answer    string     `json:"answer"`
+	Sources   []string   `json:"sources,omitempty"`   // Titles of the documents used, without repeats
+	Citations []Citation `json:"citations,omitempty"` // Excerpts given to the model, numbered as the answer's [n] markers
+	Metadata  any        `json:"metadata,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
 }
 
 // VectorDocument represents a document stored in the vector database
@@ -37,19 +69,78 @@ type VectorDocument struct {
 	Embedding []float32              `json:"-"`
 }
 
+// SearchScope limits a similarity search to the documents a user may see.
+// Documents are only ever found for the user who uploaded them. With a
+// SessionID, documents attached to other chat sessions are left out, and
+// with Tags only documents carrying at least one of the tags are searched.
+type SearchScope struct {
+	UserID    string
+	SessionID string
+	Tags      []string
+}
+
+// KnowledgeDocument is a document uploaded to the knowledge base. It is
+// stored as the chunks in vector_documents that name it as their parent.
+type KnowledgeDocument struct {
+	ID         string                 `json:"id"`
+	UserID     string                 `json:"user_id"`
+	Metadata   map[string]interface{} `json:"metadata"` // Metadata given on upload, without the chunk keys
+	ChunkCount int                    `json:"chunk_count"`
+	Chunks     []DocumentChunk        `json:"chunks,omitempty"` // Only set for a single document
+	UploadedAt time.Time              `json:"uploaded_at"`      // When the current version was uploaded
+}
+
+// DocumentChunk is one chunk of a knowledge base document
+type DocumentChunk struct {
+	ID      string `json:"id"`
+	Index   int    `json:"index"`
+	Offset  int    `json:"offset"` // Character offset in the document text
+	Content string `json:"content"`
+}
+
 // ChatbotRepository defines the interface for chatbot operations
 type ChatbotRepository interface {
+	// Session management
+	EnsureSession(ctx context.Context, sessionID, userID, title string) (ChatSession, error)
+	GetSession(ctx context.Context, userID, sessionID string) (ChatSession, error)
+	ListSessions(ctx context.Context, userID string) ([]ChatSession, error)
+	// LinkSessionResume links a resume to a session that has none, and
+	// returns ErrSessionResumeLinked if another resume got there first
+	LinkSessionResume(ctx context.Context, userID, sessionID, resumeID string) error
+	
 	// Message management
 	SaveMessage(ctx context.Context, message ChatMessage) (ChatMessage, error)
+	// SaveTurn saves a user message and the assistant's answer to it together
+	SaveTurn(ctx context.Context, question, answer ChatMessage) (ChatMessage, ChatMessage, error)
 	GetSessionMessages(ctx context.Context, sessionID string) ([]ChatMessage, error)
 	
 	// Vector operations
 	StoreDocument(ctx context.Context, doc VectorDocument) error
-	SearchSimilarDocuments(ctx context.Context, embedding []float32, limit int) ([]VectorDocument, error)
+	SearchSimilarDocuments(ctx context.Context, embedding []float32, scope SearchScope, limit int) ([]VectorDocument, error)
+	// RetrieveDocuments combines similarity and full-text search for a query.
+	// The embedding may be nil, leaving only the full-text search.
+	RetrieveDocuments(ctx context.Context, query string, embedding []float32, scope SearchScope, limit int) ([]RetrievedDocument, error)
 	
 	// Query handling
-	ProcessQuery(ctx context.Context, sessionID string, query string) (ChatResponse, error)
+	// The scope names the session the query belongs to and limits the
+	// documents used to answer it. The query and the answer are saved as one
+	// turn once the LLM has answered; when it fails to, nothing is saved and
+	// ErrLLMUnavailable is returned.
+	ProcessQuery(ctx context.Context, scope SearchScope, query string) (ChatResponse, error)
+	ProcessQueryStream(ctx context.Context, scope SearchScope, query string, onDelta StreamHandler) (ChatResponse, error)
+	
+	// Resume extraction
+	ExtractResume(ctx context.Context, messages []ChatMessage) (Resume, error)
 	
-	// Document management helper
-	AddDocument(ctx context.Context, content string, metadata map[string]interface{}) error
+	// Document management
+	// AddDocument splits a user's document into chunks and stores them. With
+	// an empty documentID a new document is created; otherwise the user's
+	// document with that ID is replaced. It returns the document ID and the
+	// number of chunks.
+	AddDocument(ctx context.Context, userID, documentID, content string, metadata map[string]interface{}) (string, int, error)
+	ListDocuments(ctx context.Context, userID string) ([]KnowledgeDocument, error)
+	GetDocument(ctx context.Context, userID, documentID string) (KnowledgeDocument, error)
+	DeleteDocument(ctx context.Context, userID, documentID string) error
+	// DeleteDocuments removes all of a user's documents and returns how many there were
+	DeleteDocuments(ctx context.Context, userID string) (int, error)
 } 
\ No newline at end of file
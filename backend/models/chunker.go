@@ -0,0 +1,176 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Default chunk settings, in characters. Around 1000 characters keeps a
+// chunk to one topic while leaving room for several in the prompt.
+const (
+	DefaultChunkSize    = 1000
+	DefaultChunkOverlap = 150
+)
+
+var (
+	// paragraphBreak separates paragraphs: a blank line, possibly with spaces on it
+	paragraphBreak = regexp.MustCompile(`\n[ \t]*\n\s*`)
+	// sentenceEnd matches the end of a sentence and the space after it
+	sentenceEnd = regexp.MustCompile(`[.!?]+["')\]]*\s+`)
+)
+
+// Chunk is a piece of a document that is embedded and retrieved on its own
+type Chunk struct {
+	Index   int    // Position of the chunk in the document
+	Offset  int    // Character offset of the chunk in the document text
+	Content string // Text of the chunk
+}
+
+// Chunker splits documents into overlapping chunks of at most Size
+// characters. Chunks end at paragraph breaks where possible, then at
+// sentence ends, and only split inside a sentence that is too long on its
+// own. Each chunk starts with up to Overlap characters of whole paragraphs
+// or sentences from the end of the one before it.
+type Chunker struct {
+	Size    int
+	Overlap int
+}
+
+// NewChunker creates a chunker, using the defaults for a size that is not
+// positive and limiting the overlap to half the size
+func NewChunker(size, overlap int) Chunker {
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+	if overlap < 0 {
+		overlap = 0
+	}
+	if overlap > size/2 {
+		overlap = size / 2
+	}
+	return Chunker{Size: size, Overlap: overlap}
+}
+
+// span is a range of bytes in the document text
+type span struct {
+	start, end int
+}
+
+// Split cuts text into chunks. Chunk content is taken from text as is, so
+// a chunk's Offset points at its first character.
+func (c Chunker) Split(text string) []Chunk {
+	units := c.units(text)
+	if len(units) == 0 {
+		return nil
+	}
+
+	// runes counts the characters between two byte offsets
+	runes := func(start, end int) int {
+		return utf8.RuneCountInString(text[start:end])
+	}
+
+	var chunks []Chunk
+	offset, offsetByte := 0, 0
+	for first := 0; first < len(units); {
+		// Take as many units as fit
+		last := first
+		for last+1 < len(units) && runes(units[first].start, units[last+1].end) <= c.Size {
+			last++
+		}
+
+		start, end := units[first].start, units[last].end
+		offset += runes(offsetByte, start)
+		offsetByte = start
+		chunks = append(chunks, Chunk{
+			Index:   len(chunks),
+			Offset:  offset,
+			Content: text[start:end],
+		})
+		if last == len(units)-1 {
+			break
+		}
+
+		// The next chunk repeats the trailing units that fit in the overlap
+		next := last + 1
+		for next-1 > first && runes(units[next-1].start, end) <= c.Overlap {
+			next--
+		}
+		// but only as many as leave room for new text
+		for next <= last && runes(units[next].start, units[last+1].end) > c.Size {
+			next++
+		}
+		first = next
+	}
+	return chunks
+}
+
+// units splits text into paragraphs, splitting paragraphs longer than Size
+// into sentences and sentences longer than Size at spaces
+func (c Chunker) units(text string) []span {
+	var units []span
+	for _, paragraph := range splitSpans(text, span{0, len(text)}, paragraphBreak) {
+		if utf8.RuneCountInString(text[paragraph.start:paragraph.end]) <= c.Size {
+			units = append(units, paragraph)
+			continue
+		}
+		for _, sentence := range splitSpans(text, paragraph, sentenceEnd) {
+			units = append(units, c.splitLong(text, sentence)...)
+		}
+	}
+	return units
+}
+
+// splitSpans cuts s at the matches of sep, trimming space from the pieces
+// and leaving out empty ones. Separators that end a sentence stay with it.
+func splitSpans(text string, s span, sep *regexp.Regexp) []span {
+	var spans []span
+	add := func(start, end int) {
+		if piece := trimSpan(text, span{start, end}); piece.end > piece.start {
+			spans = append(spans, piece)
+		}
+	}
+
+	from := s.start
+	for _, m := range sep.FindAllStringIndex(text[s.start:s.end], -1) {
+		add(from, s.start+m[1])
+		from = s.start + m[1]
+	}
+	add(from, s.end)
+	return spans
+}
+
+// splitLong cuts a span longer than Size into pieces of at most Size
+// characters, at the last space that fits where there is one
+func (c Chunker) splitLong(text string, s span) []span {
+	var pieces []span
+	for s.start < s.end {
+		// Find the byte offset after Size characters
+		cut, count := s.start, 0
+		for cut < s.end && count < c.Size {
+			_, width := utf8.DecodeRuneInString(text[cut:s.end])
+			cut += width
+			count++
+		}
+		if cut < s.end {
+			if space := strings.LastIndexFunc(text[s.start:cut], unicode.IsSpace); space > 0 {
+				cut = s.start + space
+			}
+		}
+		if piece := trimSpan(text, span{s.start, cut}); piece.end > piece.start {
+			pieces = append(pieces, piece)
+		}
+		s.start = cut
+	}
+	return pieces
+}
+
+// trimSpan narrows s to leave out the space around its text
+func trimSpan(text string, s span) span {
+	piece := text[s.start:s.end]
+	trimmed := strings.TrimLeftFunc(piece, unicode.IsSpace)
+	s.start += len(piece) - len(trimmed)
+	s.end = s.start + len(strings.TrimRightFunc(trimmed, unicode.IsSpace))
+	return s
+}
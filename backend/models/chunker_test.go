@@ -0,0 +1,113 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNewChunker(t *testing.T) {
+	tests := []struct {
+		name          string
+		size, overlap int
+		want          Chunker
+	}{
+		{"keeps valid settings", 500, 100, Chunker{Size: 500, Overlap: 100}},
+		{"defaults a size that is not positive", 0, 100, Chunker{Size: DefaultChunkSize, Overlap: 100}},
+		{"clears a negative overlap", 500, -1, Chunker{Size: 500, Overlap: 0}},
+		{"limits the overlap to half the size", 500, 400, Chunker{Size: 500, Overlap: 250}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewChunker(tt.size, tt.overlap); got != tt.want {
+				t.Errorf("NewChunker(%d, %d) = %+v, want %+v", tt.size, tt.overlap, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunkerSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		chunker Chunker
+		text    string
+		want    []string
+	}{
+		{
+			name:    "empty text has no chunks",
+			chunker: Chunker{Size: 50},
+			text:    " \n\n \t",
+			want:    nil,
+		},
+		{
+			name:    "short text is one chunk",
+			chunker: Chunker{Size: 50},
+			text:    "  Hello world.  ",
+			want:    []string{"Hello world."},
+		},
+		{
+			name:    "paragraphs are kept together while they fit",
+			chunker: Chunker{Size: 30},
+			text:    "First paragraph.\n\nSecond one.\n\nThird paragraph here.",
+			want:    []string{"First paragraph.\n\nSecond one.", "Third paragraph here."},
+		},
+		{
+			name:    "long paragraphs split at sentence ends",
+			chunker: Chunker{Size: 30},
+			text:    "One sentence here. Another sentence! And a third one?",
+			want:    []string{"One sentence here.", "Another sentence!", "And a third one?"},
+		},
+		{
+			name:    "long sentences split at spaces",
+			chunker: Chunker{Size: 10},
+			text:    "alpha beta gamma delta",
+			want:    []string{"alpha beta", "gamma", "delta"},
+		},
+		{
+			name:    "words longer than the size are cut",
+			chunker: Chunker{Size: 4},
+			text:    "abcdefghij",
+			want:    []string{"abcd", "efgh", "ij"},
+		},
+		{
+			name:    "chunks repeat whole units from the end of the one before",
+			chunker: Chunker{Size: 30, Overlap: 14},
+			text:    "Alpha one.\n\nBravo two.\n\nCharlie three.\n\nDelta four.",
+			want:    []string{"Alpha one.\n\nBravo two.", "Bravo two.\n\nCharlie three.", "Charlie three.\n\nDelta four."},
+		},
+		{
+			name:    "sizes count characters, not bytes",
+			chunker: Chunker{Size: 12},
+			text:    "Čeština ěšč.\n\nŽluťoučký.",
+			want:    []string{"Čeština ěšč.", "Žluťoučký."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := tt.chunker.Split(tt.text)
+
+			var got []string
+			for i, chunk := range chunks {
+				got = append(got, chunk.Content)
+
+				if chunk.Index != i {
+					t.Errorf("chunk %d has index %d", i, chunk.Index)
+				}
+				if n := utf8.RuneCountInString(chunk.Content); n > tt.chunker.Size {
+					t.Errorf("chunk %d has %d characters, over the size of %d", i, n, tt.chunker.Size)
+				}
+				// The offset counts characters up to the chunk's content
+				if runes := []rune(tt.text); chunk.Offset+utf8.RuneCountInString(chunk.Content) > len(runes) ||
+					!strings.HasPrefix(string(runes[chunk.Offset:]), chunk.Content) {
+					t.Errorf("chunk %d offset %d does not point at %q", i, chunk.Offset, chunk.Content)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
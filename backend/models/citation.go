@@ -0,0 +1,88 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// snippetSize is the length of the quote in a citation, in characters
+const snippetSize = 240
+
+// citationMarker matches the [n] markers the model cites excerpts with,
+// including several numbers in one marker such as [1, 3]
+var citationMarker = regexp.MustCompile(`\[(\d+(?:\s*,\s*\d+)*)\]`)
+
+// Citation is a document excerpt given to the model to answer a query. The
+// answer refers to it as [Number].
+type Citation struct {
+	Number     int     `json:"number"`
+	DocumentID string  `json:"document_id"`
+	ChunkID    string  `json:"chunk_id"`
+	Title      string  `json:"title"`
+	Score      float64 `json:"score"`   // Cosine similarity to the query, 0 for full-text matches only
+	Snippet    string  `json:"snippet"` // Start of the excerpt
+	Cited      bool    `json:"cited"`   // Whether the answer refers to it
+}
+
+// citationInstructions tells the model how to use and cite the excerpts
+const citationInstructions = `Use the following excerpts from the user's documents to answer their question, if relevant.
+When you use an excerpt, cite it with its number in square brackets, for example [1] or [1, 3].
+Only cite excerpts you used, and never make up numbers.`
+
+// citations describes the documents given to the model, numbered as in the
+// prompt, and marks the ones the answer cites. Markers with numbers that were
+// not in the prompt are ignored.
+func citations(answer string, docs []RetrievedDocument) []Citation {
+	cited := map[int]bool{}
+	for _, match := range citationMarker.FindAllStringSubmatch(answer, -1) {
+		for _, number := range strings.Split(match[1], ",") {
+			if n, err := strconv.Atoi(strings.TrimSpace(number)); err == nil {
+				cited[n] = true
+			}
+		}
+	}
+
+	result := make([]Citation, 0, len(docs))
+	for i, doc := range docs {
+		documentID, _ := doc.Metadata["parent_id"].(string)
+		result = append(result, Citation{
+			Number:     i + 1,
+			DocumentID: documentID,
+			ChunkID:    doc.ID,
+			Title:      documentTitle(doc.VectorDocument),
+			Score:      doc.Scores.Similarity,
+			Snippet:    snippet(doc.Content),
+			Cited:      cited[i+1],
+		})
+	}
+	return result
+}
+
+// documentTitle names a document by its title, source or file name metadata,
+// falling back to its ID
+func documentTitle(doc VectorDocument) string {
+	for _, key := range []string{"title", "source", "filename", "parent_id"} {
+		if title, ok := doc.Metadata[key].(string); ok && strings.TrimSpace(title) != "" {
+			return strings.TrimSpace(title)
+		}
+	}
+	return doc.ID
+}
+
+// snippet returns the start of a text, cut at a word boundary, with its
+// whitespace collapsed
+func snippet(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= snippetSize {
+		return text
+	}
+
+	cut := string(runes[:snippetSize])
+	if space := strings.LastIndexFunc(cut, unicode.IsSpace); space > len(cut)/2 {
+		cut = cut[:space]
+	}
+	return strings.TrimRightFunc(cut, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsPunct(r) }) + "..."
+}
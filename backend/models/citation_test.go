@@ -0,0 +1,109 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCitations(t *testing.T) {
+	docs := []RetrievedDocument{
+		{
+			VectorDocument: VectorDocument{ID: "chunk-a", Content: "First excerpt.", Metadata: map[string]interface{}{"parent_id": "doc-1", "title": "Resume guide"}},
+			Scores:         RetrievalScores{Similarity: 0.9},
+		},
+		{
+			VectorDocument: VectorDocument{ID: "chunk-b", Content: "Second excerpt.", Metadata: map[string]interface{}{"parent_id": "doc-2", "filename": "cover.pdf"}},
+		},
+		{
+			VectorDocument: VectorDocument{ID: "chunk-c", Content: "Third excerpt."},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		answer string
+		cited  []bool // By citation number
+	}{
+		{"no markers", "A plain answer.", []bool{false, false, false}},
+		{"single markers", "See [1] and [3].", []bool{true, false, true}},
+		{"several numbers in one marker", "Both agree [1, 2].", []bool{true, true, false}},
+		{"numbers that were not in the prompt are ignored", "As [4] and [0] say.", []bool{false, false, false}},
+		{"other brackets are not markers", "Use [brackets] like [a].", []bool{false, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := citations(tt.answer, docs)
+			if len(got) != len(docs) {
+				t.Fatalf("citations() = %d citations, want %d", len(got), len(docs))
+			}
+
+			var cited []bool
+			for _, citation := range got {
+				cited = append(cited, citation.Cited)
+			}
+			if !reflect.DeepEqual(cited, tt.cited) {
+				t.Errorf("cited = %v, want %v", cited, tt.cited)
+			}
+		})
+	}
+
+	// Citations are numbered in prompt order and describe their chunk
+	want := []Citation{
+		{Number: 1, DocumentID: "doc-1", ChunkID: "chunk-a", Title: "Resume guide", Score: 0.9, Snippet: "First excerpt."},
+		{Number: 2, DocumentID: "doc-2", ChunkID: "chunk-b", Title: "cover.pdf", Snippet: "Second excerpt."},
+		{Number: 3, ChunkID: "chunk-c", Title: "chunk-c", Snippet: "Third excerpt."},
+	}
+	if got := citations("", docs); !reflect.DeepEqual(got, want) {
+		t.Errorf("citations() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDocumentTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		want     string
+	}{
+		{"title first", map[string]interface{}{"title": "Guide", "source": "guide.txt"}, "Guide"},
+		{"then source", map[string]interface{}{"source": " guide.txt ", "filename": "upload.txt"}, "guide.txt"},
+		{"then file name", map[string]interface{}{"title": "  ", "filename": "upload.txt"}, "upload.txt"},
+		{"then document ID", map[string]interface{}{"parent_id": "doc-1"}, "doc-1"},
+		{"then chunk ID", map[string]interface{}{"title": 42}, "chunk-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := documentTitle(VectorDocument{ID: "chunk-1", Metadata: tt.metadata}); got != tt.want {
+				t.Errorf("documentTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	long := strings.Repeat("word ", 100)
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"short text is kept", "Short text.", "Short text."},
+		{"whitespace is collapsed", "Line one\n\n  line   two", "Line one line two"},
+		{"long text is cut at a word", long, strings.TrimSpace(strings.Repeat("word ", 48)) + "..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := snippet(tt.text)
+			if got != tt.want {
+				t.Errorf("snippet() = %q, want %q", got, tt.want)
+			}
+			if n := len([]rune(got)); n > snippetSize+len("...") {
+				t.Errorf("snippet() has %d characters, want at most %d", n, snippetSize+len("..."))
+			}
+		})
+	}
+}
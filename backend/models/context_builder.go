@@ -0,0 +1,276 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"resume.in/backend/utils"
+)
+
+// messageOverhead is the estimated number of tokens a chat message costs on
+// top of its content, for the role and the separators around it
+const messageOverhead = 4
+
+// minTruncatedTokens is the smallest part of a document worth keeping when it
+// has to be truncated to fit
+const minTruncatedTokens = 100
+
+// recentHistory is the number of latest messages ranked above every document,
+// so that a follow-up question keeps the exchange it refers to
+const recentHistory = 2
+
+// modelContextWindows maps parts of model names to their context window in
+// tokens. The first match wins, so more specific names come first.
+var modelContextWindows = []struct {
+	name   string
+	tokens int
+}{
+	{"claude", 200000},
+	{"gemini", 1000000},
+	{"gpt-4o", 128000},
+	{"gpt-4.1", 1000000},
+	{"gpt-4-turbo", 128000},
+	{"gpt-4", 8192},
+	{"gpt-3.5", 16385},
+	{"llama-3.1", 128000},
+	{"llama-3.2", 128000},
+	{"llama-3.3", 128000},
+	{"llama-3", 8192},
+	{"mixtral", 32768},
+	{"mistral", 32768},
+	{"qwen", 32768},
+}
+
+// defaultContextWindow is assumed for models that are not listed
+const defaultContextWindow = 8192
+
+// ModelContextWindow returns the context window of a model in tokens, or a
+// conservative default for unknown models
+func ModelContextWindow(model string) int {
+	model = strings.ToLower(model)
+	for _, known := range modelContextWindows {
+		if strings.Contains(model, known.name) {
+			return known.tokens
+		}
+	}
+	return defaultContextWindow
+}
+
+// EstimateTokens estimates the number of tokens in a text. Tokenizers differ
+// between models, so this errs on the high side: about four characters per
+// token for ASCII text and a token for every other character.
+func EstimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// truncateToTokens cuts text to at most the given number of estimated
+// tokens, at a word boundary where there is one
+func truncateToTokens(text string, tokens int) string {
+	if EstimateTokens(text) <= tokens {
+		return text
+	}
+
+	// Stop before the cut-off marker would no longer fit
+	limit := tokens - EstimateTokens(" ...")
+	ascii, other, end := 0, 0, 0
+	for i, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+		if (ascii+3)/4+other > limit {
+			break
+		}
+		end = i + utf8.RuneLen(r)
+	}
+
+	cut := text[:end]
+	if space := strings.LastIndexFunc(cut, unicode.IsSpace); space > len(cut)/2 {
+		cut = cut[:space]
+	}
+	return strings.TrimRightFunc(cut, unicode.IsSpace) + " ..."
+}
+
+// ContextBuilder assembles the prompt for a chat query within a model's
+// context window. The system prompt, the summary of the earlier conversation
+// and the query always go in. The rest of the window, minus the tokens
+// reserved for the answer and a margin for the estimate being off, goes to
+// the latest messages, then the retrieved documents by rank, then older
+// messages from newest to oldest. When something does not fit, it and
+// everything ranked below it is dropped, except that a document is truncated
+// if a useful part of it fits.
+type ContextBuilder struct {
+	Window       int // Context window of the model, in tokens
+	OutputTokens int // Tokens reserved for the answer
+}
+
+// NewContextBuilder creates a builder for the model. A window that is not
+// positive is looked up from the model name.
+func NewContextBuilder(model string, window, outputTokens int) ContextBuilder {
+	if window <= 0 {
+		window = ModelContextWindow(model)
+	}
+	if outputTokens < 0 {
+		outputTokens = 0
+	}
+	builder := ContextBuilder{Window: window, OutputTokens: outputTokens}
+	if builder.budget() == 0 {
+		utils.Warning("%d output tokens leave no room for the prompt in the %d token context window of %s", outputTokens, window, model)
+	}
+	return builder
+}
+
+// budget is the number of tokens left for the prompt, after the answer and a
+// margin for the estimate being off. It is 0 when the answer takes up the
+// whole window, so that only the parts that always go in are sent.
+func (b ContextBuilder) budget() int {
+	budget := b.Window - b.OutputTokens - b.Window/20
+	if budget < 0 {
+		return 0
+	}
+	return budget
+}
+
+// ContextReport describes what a built prompt holds and what was left out.
+// Token counts are estimates.
+type ContextReport struct {
+	BudgetTokens       int      `json:"budget_tokens"`
+	UsedTokens         int      `json:"used_tokens"`
+	Documents          int      `json:"documents"`
+	DroppedDocuments   []string `json:"dropped_documents,omitempty"`   // IDs of chunks left out
+	TruncatedDocuments []string `json:"truncated_documents,omitempty"` // IDs of chunks cut short
+	HistoryMessages    int      `json:"history_messages"`
+	DroppedMessages    int      `json:"dropped_messages,omitempty"` // Older messages left out
+	QueryTruncated     bool     `json:"query_truncated,omitempty"`
+	SummaryTokens      int      `json:"summary_tokens,omitempty"` // Size of the session summary
+}
+
+// BuiltContext is a prompt ready for the LLM
+type BuiltContext struct {
+	Messages  []Message
+	Documents []RetrievedDocument // The documents in the prompt, numbered in order, as they were included
+	Report    ContextReport
+}
+
+// Build assembles the prompt from the system prompt, the summary of the
+// conversation before history, the retrieved documents in rank order, the
+// conversation history from oldest to newest and the query
+func (b ContextBuilder) Build(system, summary string, docs []RetrievedDocument, history []ChatMessage, query string) BuiltContext {
+	budget := b.budget()
+	report := ContextReport{BudgetTokens: budget}
+
+	// The instructions for citing are only needed with documents, but are
+	// counted up front so that adding the first document cannot overflow
+	used := EstimateTokens(system) + messageOverhead
+	if len(docs) > 0 {
+		used += EstimateTokens(" " + citationInstructions + "\n\n")
+	}
+	if summary != "" {
+		summary = summaryIntro + summary
+		report.SummaryTokens = EstimateTokens(summary)
+		used += report.SummaryTokens + messageOverhead
+	}
+	queryTokens := EstimateTokens(query) + messageOverhead
+	if available := budget - used - messageOverhead; used+queryTokens > budget && available >= minTruncatedTokens {
+		// Only a pasted document makes a query this long; keep its start
+		query = truncateToTokens(query, available)
+		queryTokens = EstimateTokens(query) + messageOverhead
+		report.QueryTruncated = true
+	}
+	used += queryTokens
+
+	// Walk the content in order of importance until something does not fit
+	recent := len(history) - min(len(history), recentHistory)
+	var kept []RetrievedDocument
+	firstMessage := len(history)
+	full := false
+
+	addMessage := func(i int) {
+		if full {
+			return
+		}
+		cost := EstimateTokens(history[i].Content) + messageOverhead
+		if used+cost > budget {
+			full = true
+			return
+		}
+		used += cost
+		firstMessage = i
+	}
+
+	for i := len(history) - 1; i >= recent; i-- {
+		addMessage(i)
+	}
+	for _, doc := range docs {
+		if full {
+			report.DroppedDocuments = append(report.DroppedDocuments, doc.ID)
+			continue
+		}
+
+		cost := EstimateTokens(excerpt(len(kept)+1, doc))
+		if used+cost > budget {
+			full = true
+			available := budget - used - (cost - EstimateTokens(doc.Content))
+			if available < minTruncatedTokens {
+				report.DroppedDocuments = append(report.DroppedDocuments, doc.ID)
+				continue
+			}
+			doc.Content = truncateToTokens(doc.Content, available)
+			cost = EstimateTokens(excerpt(len(kept)+1, doc))
+			report.TruncatedDocuments = append(report.TruncatedDocuments, doc.ID)
+		}
+		used += cost
+		kept = append(kept, doc)
+	}
+	for i := recent - 1; i >= 0; i-- {
+		addMessage(i)
+	}
+	if len(kept) == 0 && len(docs) > 0 {
+		// Nothing to cite after all
+		used -= EstimateTokens(" " + citationInstructions + "\n\n")
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString(system)
+	if len(kept) > 0 {
+		prompt.WriteString(" " + citationInstructions + "\n\n")
+		for i, doc := range kept {
+			prompt.WriteString(excerpt(i+1, doc))
+		}
+	}
+
+	messages := []Message{{Role: "system", Content: prompt.String()}}
+	if summary != "" {
+		// Ahead of the history, which picks up where the summary ends
+		messages = append(messages, Message{Role: "system", Content: summary})
+	}
+	for _, msg := range history[firstMessage:] {
+		messages = append(messages, Message{Role: msg.Role, Content: msg.Content})
+	}
+	messages = append(messages, Message{Role: "user", Content: query})
+
+	report.UsedTokens = used
+	report.Documents = len(kept)
+	report.HistoryMessages = len(history) - firstMessage
+	report.DroppedMessages = firstMessage
+	return BuiltContext{Messages: messages, Documents: kept, Report: report}
+}
+
+// summaryIntro introduces the session summary to the model
+const summaryIntro = "Summary of the conversation so far, before the messages that follow:\n\n"
+
+// excerpt formats a retrieved document as the numbered excerpt the answer cites
+func excerpt(number int, doc RetrievedDocument) string {
+	return fmt.Sprintf("[%d] %s\n%s\n\n", number, documentTitle(doc.VectorDocument), doc.Content)
+}
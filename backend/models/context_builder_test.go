@@ -0,0 +1,253 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// tokens returns ASCII text that is estimated at exactly n tokens
+func tokens(n int) string {
+	return strings.Repeat("abc ", n)
+}
+
+func TestModelContextWindow(t *testing.T) {
+	tests := []struct {
+		model string
+		want  int
+	}{
+		{"anthropic/claude-3-sonnet:beta", 200000},
+		{"openai/gpt-4o-mini", 128000},
+		{"openai/gpt-4", 8192},
+		{"meta-llama/llama-3.1-8b-instruct", 128000},
+		{"meta-llama/llama-3-8b-instruct", 8192},
+		{"Mixtral-8x7B", 32768},
+		{"some-unknown-model", defaultContextWindow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			if got := ModelContextWindow(tt.model); got != tt.want {
+				t.Errorf("ModelContextWindow(%q) = %d, want %d", tt.model, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{"empty", "", 0},
+		{"four characters per token", "abcdefgh", 2},
+		{"partial tokens round up", "abcde", 2},
+		{"every other character is a token", "héllo", 2},
+		{"non-Latin text", "简历", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateTokens(tt.text); got != tt.want {
+				t.Errorf("EstimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateToTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		tokens int
+		want   string
+	}{
+		{"short text is kept", "a short text", 10, "a short text"},
+		{"cut at a word boundary", "one two three four five six seven eight", 6, "one two three four ..."},
+		{"cut inside a long word", strings.Repeat("x", 40), 4, "xxxxxxxxxxxx ..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateToTokens(tt.text, tt.tokens)
+			if got != tt.want {
+				t.Errorf("truncateToTokens() = %q, want %q", got, tt.want)
+			}
+			if EstimateTokens(got) > tt.tokens {
+				t.Errorf("truncateToTokens() = %d tokens, want at most %d", EstimateTokens(got), tt.tokens)
+			}
+		})
+	}
+}
+
+func TestNewContextBuilder(t *testing.T) {
+	tests := []struct {
+		name         string
+		model        string
+		window       int
+		outputTokens int
+		want         ContextBuilder
+	}{
+		{"configured window", "gpt-4", 4096, 500, ContextBuilder{Window: 4096, OutputTokens: 500}},
+		{"window from the model", "gpt-4", 0, 500, ContextBuilder{Window: 8192, OutputTokens: 500}},
+		{"negative output tokens", "gpt-4", 4096, -1, ContextBuilder{Window: 4096, OutputTokens: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewContextBuilder(tt.model, tt.window, tt.outputTokens); got != tt.want {
+				t.Errorf("NewContextBuilder() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextBuilderBuild(t *testing.T) {
+	docs := func(sizes ...int) []RetrievedDocument {
+		var result []RetrievedDocument
+		for i, size := range sizes {
+			id := fmt.Sprintf("doc-%d", i+1)
+			result = append(result, RetrievedDocument{VectorDocument: VectorDocument{ID: id, Content: tokens(size)}})
+		}
+		return result
+	}
+	history := func(sizes ...int) []ChatMessage {
+		var result []ChatMessage
+		for i, size := range sizes {
+			role := "user"
+			if i%2 == 1 {
+				role = "assistant"
+			}
+			result = append(result, ChatMessage{ID: int64(i + 1), Role: role, Content: tokens(size)})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name      string
+		builder   ContextBuilder
+		summary   string
+		docs      []RetrievedDocument
+		history   []ChatMessage
+		query     string
+		budget    int
+		documents int
+		dropped   []string // Dropped documents
+		truncated []string // Truncated documents
+		messages  int      // History messages kept
+		queryCut  bool
+	}{
+		{
+			name:      "everything fits",
+			builder:   ContextBuilder{Window: 10000, OutputTokens: 1000},
+			docs:      docs(100, 100, 100),
+			history:   history(50, 50, 50, 50),
+			query:     "What should my summary say?",
+			budget:    8500,
+			documents: 3,
+			messages:  4,
+		},
+		{
+			name:      "documents that do not fit are truncated, then dropped",
+			builder:   ContextBuilder{Window: 2000, OutputTokens: 500},
+			docs:      docs(800, 800, 800),
+			query:     "Question",
+			budget:    1400,
+			documents: 2,
+			dropped:   []string{"doc-3"},
+			truncated: []string{"doc-2"},
+		},
+		{
+			name:      "the latest messages rank above documents and older messages below them",
+			builder:   ContextBuilder{Window: 2000, OutputTokens: 500},
+			docs:      docs(500),
+			history:   history(300, 300, 300, 300),
+			query:     "Question",
+			budget:    1400,
+			documents: 1,
+			messages:  2,
+		},
+		{
+			name:     "a long query is truncated",
+			builder:  ContextBuilder{Window: 2000, OutputTokens: 500},
+			history:  history(10, 10),
+			query:    tokens(3000),
+			budget:   1400,
+			queryCut: true,
+		},
+		{
+			name:     "output tokens taking up the whole window leave no budget",
+			builder:  ContextBuilder{Window: 1000, OutputTokens: 2000},
+			summary:  "The user is a nurse.",
+			docs:     docs(10),
+			history:  history(10, 10),
+			query:    "Question",
+			budget:   0,
+			dropped:  []string{"doc-1"},
+			messages: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			built := tt.builder.Build("You are a resume assistant.", tt.summary, tt.docs, tt.history, tt.query)
+			report := built.Report
+
+			if report.BudgetTokens != tt.budget {
+				t.Errorf("BudgetTokens = %d, want %d", report.BudgetTokens, tt.budget)
+			}
+			if tt.budget > 0 && report.UsedTokens > report.BudgetTokens {
+				t.Errorf("UsedTokens = %d, over the budget of %d", report.UsedTokens, report.BudgetTokens)
+			}
+			if report.Documents != tt.documents || len(built.Documents) != tt.documents {
+				t.Errorf("Documents = %d (%d built), want %d", report.Documents, len(built.Documents), tt.documents)
+			}
+			if !reflect.DeepEqual(report.DroppedDocuments, tt.dropped) {
+				t.Errorf("DroppedDocuments = %v, want %v", report.DroppedDocuments, tt.dropped)
+			}
+			if !reflect.DeepEqual(report.TruncatedDocuments, tt.truncated) {
+				t.Errorf("TruncatedDocuments = %v, want %v", report.TruncatedDocuments, tt.truncated)
+			}
+			if report.HistoryMessages != tt.messages || report.DroppedMessages != len(tt.history)-tt.messages {
+				t.Errorf("HistoryMessages = %d, DroppedMessages = %d, want %d kept of %d", report.HistoryMessages, report.DroppedMessages, tt.messages, len(tt.history))
+			}
+			if report.QueryTruncated != tt.queryCut {
+				t.Errorf("QueryTruncated = %v, want %v", report.QueryTruncated, tt.queryCut)
+			}
+
+			// The system prompt, the summary, the kept history in order and the query
+			messages := built.Messages
+			want := 2 + tt.messages
+			if tt.summary != "" {
+				want++
+			}
+			if len(messages) != want {
+				t.Fatalf("got %d messages, want %d", len(messages), want)
+			}
+			if messages[0].Role != "system" || !strings.HasPrefix(messages[0].Content, "You are a resume assistant.") {
+				t.Errorf("first message = %+v, want the system prompt", messages[0])
+			}
+			if tt.summary != "" && messages[1].Content != summaryIntro+tt.summary {
+				t.Errorf("second message = %q, want the summary", messages[1].Content)
+			}
+			kept := tt.history[len(tt.history)-tt.messages:]
+			for i, msg := range kept {
+				if got := messages[len(messages)-1-len(kept)+i]; got.Role != msg.Role || got.Content != msg.Content {
+					t.Errorf("history message %d = %+v, want %+v", i, got, msg)
+				}
+			}
+			last := messages[len(messages)-1]
+			if last.Role != "user" || (!tt.queryCut && last.Content != tt.query) {
+				t.Errorf("last message = %+v, want the query", last)
+			}
+
+			for i, doc := range built.Documents {
+				if !strings.Contains(messages[0].Content, fmt.Sprintf("[%d] %s\n", i+1, doc.ID)) {
+					t.Errorf("system prompt does not number %s as [%d]", doc.ID, i+1)
+				}
+			}
+		})
+	}
+}
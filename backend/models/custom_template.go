@@ -0,0 +1,21 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTemplateNotFound is returned when the user has no custom template with the given name or ID
+var ErrTemplateNotFound = errors.New("template not found")
+
+// CustomTemplate is a text export template a user uploaded. It replaces the
+// built-in template of its format when an export names it.
+type CustomTemplate struct {
+	ID        string    `json:"id"`
+	OwnerID   string    `json:"ownerId,omitempty"` // ID of the user who uploaded the template
+	Name      string    `json:"name"`
+	Format    string    `json:"format"`  // Export format the template renders, such as md, html or txt
+	Content   string    `json:"content"` // Go template source
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,256 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"resume.in/backend/config"
+)
+
+// Embedder turns text into vectors for similarity search
+type Embedder interface {
+	// Embed returns one vector per input text, each of length Dimensions()
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// Dimensions is the length of every vector returned by Embed
+	Dimensions() int
+	// Name identifies the embedding model. Vectors from embedders with
+	// different names are not comparable.
+	Name() string
+}
+
+// NewEmbedder creates the embedder selected by the configuration
+func NewEmbedder(cfg *config.Config) (Embedder, error) {
+	if cfg.EmbeddingDimensions <= 0 {
+		return nil, fmt.Errorf("embedding dimensions must be positive, got %d", cfg.EmbeddingDimensions)
+	}
+
+	provider := cfg.EmbeddingProvider
+	if provider == "" {
+		// Use the remote model when we have credentials for it, otherwise stay offline
+		provider = "hash"
+		if cfg.EmbeddingAPIKey != "" && cfg.EmbeddingAPIKey != "your_openrouter_api_key" {
+			provider = "openai"
+		}
+	}
+
+	switch provider {
+	case "hash":
+		return NewHashEmbedder(cfg.EmbeddingDimensions), nil
+	case "openai":
+		return NewOpenAIEmbedder(cfg.EmbeddingBaseURL, cfg.EmbeddingAPIKey, cfg.EmbeddingModel, cfg.EmbeddingDimensions), nil
+	default:
+		return nil, fmt.Errorf("unknown embedding provider %q", provider)
+	}
+}
+
+// embedOne embeds a single text
+func embedOne(ctx context.Context, embedder Embedder, text string) ([]float32, error) {
+	vectors, err := embedder.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+// OpenAIEmbedder calls an OpenAI-compatible /embeddings endpoint. This covers
+// OpenRouter as well as local servers such as Ollama, vLLM and llama.cpp.
+type OpenAIEmbedder struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	dimensions int
+	client     *http.Client
+}
+
+// NewOpenAIEmbedder creates an embedder for an OpenAI-compatible API
+func NewOpenAIEmbedder(baseURL, apiKey, model string, dimensions int) *OpenAIEmbedder {
+	return &OpenAIEmbedder{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		model:      model,
+		dimensions: dimensions,
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// embeddingRequest is the request body of the /embeddings endpoint
+type embeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embeddingResponse is the response body of the /embeddings endpoint
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Embed implements Embedder
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+
+	requestBody, err := json.Marshal(embeddingRequest{Model: e.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", e.baseURL+"/embeddings", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var embeddingResp embeddingResponse
+	if err := json.Unmarshal(body, &embeddingResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response (status %d): %w", resp.StatusCode, err)
+	}
+
+	if embeddingResp.Error != nil {
+		return nil, fmt.Errorf("embedding error: %s", embeddingResp.Error.Message)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embedding request failed with status %d", resp.StatusCode)
+	}
+
+	if len(embeddingResp.Data) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embeddingResp.Data))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, item := range embeddingResp.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding index %d out of range", item.Index)
+		}
+		if len(item.Embedding) != e.dimensions {
+			return nil, fmt.Errorf("model %s returned %d dimensions but EMBEDDING_DIMENSIONS is %d", e.model, len(item.Embedding), e.dimensions)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+
+	return vectors, nil
+}
+
+// Dimensions implements Embedder
+func (e *OpenAIEmbedder) Dimensions() int {
+	return e.dimensions
+}
+
+// Name implements Embedder
+func (e *OpenAIEmbedder) Name() string {
+	return "openai:" + e.model
+}
+
+// HashEmbedder is a deterministic embedder that needs no network access.
+// It hashes words and character trigrams into a fixed number of buckets
+// (the "hashing trick"), so texts that share vocabulary end up close together.
+// It is much weaker than a trained model but keeps retrieval meaningful offline.
+type HashEmbedder struct {
+	dimensions int
+}
+
+// NewHashEmbedder creates a hashing embedder with the given vector size
+func NewHashEmbedder(dimensions int) *HashEmbedder {
+	return &HashEmbedder{dimensions: dimensions}
+}
+
+// Embed implements Embedder
+func (e *HashEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.embed(text)
+	}
+	return vectors, nil
+}
+
+// embed hashes the features of a single text into a normalized vector
+func (e *HashEmbedder) embed(text string) []float32 {
+	vector := make([]float32, e.dimensions)
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	// Text without words, such as punctuation or emoji, is hashed whole so its
+	// vector is not all zeros, which has no cosine distance to anything
+	if len(words) == 0 {
+		e.add(vector, "t:"+strings.TrimSpace(text), 1.0)
+	}
+
+	for _, word := range words {
+		e.add(vector, "w:"+word, 1.0)
+
+		// Character trigrams let related word forms ("develop", "developer") overlap
+		runes := []rune("^" + word + "$")
+		for i := 0; i+3 <= len(runes); i++ {
+			e.add(vector, "c:"+string(runes[i:i+3]), 0.5)
+		}
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range vector {
+			vector[i] *= scale
+		}
+	}
+
+	return vector
+}
+
+// add hashes a feature into the vector with a pseudo-random sign
+func (e *HashEmbedder) add(vector []float32, feature string, weight float32) {
+	h := fnv.New64a()
+	h.Write([]byte(feature))
+	sum := h.Sum64()
+
+	index := int(sum % uint64(len(vector)))
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	vector[index] += weight
+}
+
+// Dimensions implements Embedder
+func (e *HashEmbedder) Dimensions() int {
+	return e.dimensions
+}
+
+// Name implements Embedder
+func (e *HashEmbedder) Name() string {
+	return "hash-v1"
+}
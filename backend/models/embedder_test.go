@@ -0,0 +1,212 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"resume.in/backend/config"
+)
+
+// cosine returns the cosine similarity of two vectors of the same length
+func cosine(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	return dot / math.Sqrt(na*nb)
+}
+
+func TestHashEmbedder(t *testing.T) {
+	embedder := NewHashEmbedder(64)
+
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"words", "Senior Go developer"},
+		{"numbers", "2019 2021"},
+		{"punctuation only", "?!"},
+		{"emoji only", "🚀🚀"},
+		{"empty", ""},
+		{"whitespace", " \n\t"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vectors, err := embedder.Embed(context.Background(), []string{tt.text, tt.text})
+			if err != nil {
+				t.Fatalf("Embed() error = %v", err)
+			}
+			vector := vectors[0]
+			if len(vector) != embedder.Dimensions() {
+				t.Fatalf("Embed() = %d dimensions, want %d", len(vector), embedder.Dimensions())
+			}
+
+			// Every vector has unit length, so cosine distance is defined
+			var norm float64
+			for _, v := range vector {
+				norm += float64(v) * float64(v)
+			}
+			if math.Abs(norm-1) > 1e-5 {
+				t.Errorf("Embed(%q) has squared norm %v, want 1", tt.text, norm)
+			}
+			if c := cosine(vector, vectors[1]); math.Abs(c-1) > 1e-5 {
+				t.Errorf("Embed(%q) is not deterministic: similarity %v", tt.text, c)
+			}
+		})
+	}
+}
+
+func TestHashEmbedderSimilarity(t *testing.T) {
+	embedder := NewHashEmbedder(256)
+	vectors, err := embedder.Embed(context.Background(), []string{
+		"experienced Go developer", "Go developers with experience", "banana bread recipe",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	related, unrelated := cosine(vectors[0], vectors[1]), cosine(vectors[0], vectors[2])
+	if related <= unrelated {
+		t.Errorf("similarity of related texts %v is not above unrelated texts %v", related, unrelated)
+	}
+}
+
+func TestNewEmbedder(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.Config
+		wantName string
+		wantErr  bool
+	}{
+		{
+			name:     "hash without an API key",
+			cfg:      config.Config{EmbeddingDimensions: 8},
+			wantName: "hash-v1",
+		},
+		{
+			name:     "hash while the key is the placeholder",
+			cfg:      config.Config{EmbeddingDimensions: 8, EmbeddingAPIKey: "your_openrouter_api_key"},
+			wantName: "hash-v1",
+		},
+		{
+			name:     "OpenAI-compatible with an API key",
+			cfg:      config.Config{EmbeddingDimensions: 8, EmbeddingAPIKey: "key", EmbeddingModel: "text-embedding-3-small"},
+			wantName: "openai:text-embedding-3-small",
+		},
+		{
+			name:     "explicit provider",
+			cfg:      config.Config{EmbeddingDimensions: 8, EmbeddingAPIKey: "key", EmbeddingProvider: "hash"},
+			wantName: "hash-v1",
+		},
+		{
+			name:    "unknown provider",
+			cfg:     config.Config{EmbeddingDimensions: 8, EmbeddingProvider: "word2vec"},
+			wantErr: true,
+		},
+		{
+			name:    "no dimensions",
+			cfg:     config.Config{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			embedder, err := NewEmbedder(&tt.cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NewEmbedder() = %s, want an error", embedder.Name())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewEmbedder() error = %v", err)
+			}
+			if embedder.Name() != tt.wantName || embedder.Dimensions() != tt.cfg.EmbeddingDimensions {
+				t.Errorf("NewEmbedder() = %s with %d dimensions, want %s with %d",
+					embedder.Name(), embedder.Dimensions(), tt.wantName, tt.cfg.EmbeddingDimensions)
+			}
+		})
+	}
+}
+
+func TestOpenAIEmbedder(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		reply   string
+		want    [][]float32
+		wantErr string
+	}{
+		{
+			name:   "vectors are returned in input order",
+			status: http.StatusOK,
+			reply:  `{"data": [{"index": 1, "embedding": [0, 1]}, {"index": 0, "embedding": [1, 0]}]}`,
+			want:   [][]float32{{1, 0}, {0, 1}},
+		},
+		{
+			name:    "wrong dimensions",
+			status:  http.StatusOK,
+			reply:   `{"data": [{"index": 0, "embedding": [1, 0, 0]}, {"index": 1, "embedding": [0, 1, 0]}]}`,
+			wantErr: "returned 3 dimensions",
+		},
+		{
+			name:    "missing vectors",
+			status:  http.StatusOK,
+			reply:   `{"data": [{"index": 0, "embedding": [1, 0]}]}`,
+			wantErr: "expected 2 embeddings, got 1",
+		},
+		{
+			name:    "API error",
+			status:  http.StatusUnauthorized,
+			reply:   `{"error": {"message": "invalid key"}}`,
+			wantErr: "invalid key",
+		},
+		{
+			name:    "error status without a message",
+			status:  http.StatusBadGateway,
+			reply:   `{}`,
+			wantErr: "status 502",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req embeddingRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model != "model" || len(req.Input) != 2 {
+					t.Errorf("request = %+v, %v", req, err)
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer key" {
+					t.Errorf("Authorization = %q", got)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.reply))
+			}))
+			defer server.Close()
+
+			vectors, err := NewOpenAIEmbedder(server.URL+"/", "key", "model", 2).Embed(context.Background(), []string{"a", "b"})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Embed() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Embed() error = %v", err)
+			}
+			if !reflect.DeepEqual(vectors, tt.want) {
+				t.Errorf("Embed() = %v, want %v", vectors, tt.want)
+			}
+		})
+	}
+}
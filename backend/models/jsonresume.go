@@ -0,0 +1,383 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// JSONResumeSchema is the JSON Resume schema exported documents declare
+const JSONResumeSchema = "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json"
+
+// JSONResume is a resume in the open JSON Resume format (https://jsonresume.org/schema).
+// Sections Resume has no equivalent for are kept raw so imports can report them.
+type JSONResume struct {
+	Schema       string                  `json:"$schema,omitempty"`
+	Basics       JSONResumeBasics        `json:"basics"`
+	Work         []JSONResumeWork        `json:"work,omitempty"`
+	Volunteer    []json.RawMessage       `json:"volunteer,omitempty" swaggertype:"array,object"`
+	Education    []JSONResumeEducation   `json:"education,omitempty"`
+	Awards       []json.RawMessage       `json:"awards,omitempty" swaggertype:"array,object"`
+	Certificates []JSONResumeCertificate `json:"certificates,omitempty"`
+	Publications []json.RawMessage       `json:"publications,omitempty" swaggertype:"array,object"`
+	Skills       []JSONResumeSkill       `json:"skills,omitempty"`
+	Languages    []json.RawMessage       `json:"languages,omitempty" swaggertype:"array,object"`
+	Interests    []json.RawMessage       `json:"interests,omitempty" swaggertype:"array,object"`
+	References   []json.RawMessage       `json:"references,omitempty" swaggertype:"array,object"`
+	Projects     []JSONResumeProject     `json:"projects,omitempty"`
+	Meta         json.RawMessage         `json:"meta,omitempty" swaggertype:"object"`
+}
+
+// JSONResumeBasics holds the contact details of a JSON Resume
+type JSONResumeBasics struct {
+	Name     string              `json:"name,omitempty"`
+	Label    string              `json:"label,omitempty"`
+	Image    string              `json:"image,omitempty"`
+	Email    string              `json:"email,omitempty"`
+	Phone    string              `json:"phone,omitempty"`
+	URL      string              `json:"url,omitempty"`
+	Summary  string              `json:"summary,omitempty"`
+	Location *JSONResumeLocation `json:"location,omitempty"`
+	Profiles []JSONResumeProfile `json:"profiles,omitempty"`
+}
+
+// JSONResumeLocation is a postal address
+type JSONResumeLocation struct {
+	Address     string `json:"address,omitempty"`
+	PostalCode  string `json:"postalCode,omitempty"`
+	City        string `json:"city,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+	Region      string `json:"region,omitempty"`
+}
+
+// JSONResumeProfile is a social network profile
+type JSONResumeProfile struct {
+	Network  string `json:"network,omitempty"`
+	Username string `json:"username,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+// JSONResumeWork is a work entry. Company is the field's name in schema versions before 1.0.
+type JSONResumeWork struct {
+	Name        string   `json:"name,omitempty"`
+	Company     string   `json:"company,omitempty"`
+	Position    string   `json:"position,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Location    string   `json:"location,omitempty"`
+	Description string   `json:"description,omitempty"` // Describes the organisation, not the role
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+}
+
+// JSONResumeEducation is an education entry
+type JSONResumeEducation struct {
+	Institution string   `json:"institution,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Area        string   `json:"area,omitempty"`
+	StudyType   string   `json:"studyType,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Score       string   `json:"score,omitempty"`
+	Courses     []string `json:"courses,omitempty"`
+}
+
+// JSONResumeCertificate is a certificate entry
+type JSONResumeCertificate struct {
+	Name   string `json:"name,omitempty"`
+	Date   string `json:"date,omitempty"`
+	Issuer string `json:"issuer,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+// JSONResumeSkill is a skill entry
+type JSONResumeSkill struct {
+	Name     string   `json:"name,omitempty"`
+	Level    string   `json:"level,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// JSONResumeProject is a project entry
+type JSONResumeProject struct {
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Entity      string   `json:"entity,omitempty"`
+	Type        string   `json:"type,omitempty"`
+}
+
+// jsonResumeSections are the top-level keys of the JSON Resume schema
+var jsonResumeSections = map[string]bool{
+	"$schema": true, "basics": true, "work": true, "volunteer": true, "education": true,
+	"awards": true, "certificates": true, "publications": true, "skills": true,
+	"languages": true, "interests": true, "references": true, "projects": true, "meta": true,
+}
+
+// droppedFields collects the paths of fields a conversion could not keep,
+// such as "work[].url", without repeating a path for every entry
+type droppedFields map[string]bool
+
+// add records path when value is set
+func (d droppedFields) add(path string, set bool) {
+	if set {
+		d[path] = true
+	}
+}
+
+// list returns the recorded paths in order
+func (d droppedFields) list() []string {
+	paths := make([]string, 0, len(d))
+	for p := range d {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// ParseJSONResume decodes a JSON Resume document. Besides the resume, it returns the
+// paths of fields Resume has no place for, which are left out of the conversion.
+func ParseJSONResume(data []byte) (Resume, []string, error) {
+	var doc JSONResume
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Resume{}, nil, fmt.Errorf("invalid JSON Resume: %v", err)
+	}
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return Resume{}, nil, fmt.Errorf("invalid JSON Resume: %v", err)
+	}
+
+	resume, dropped := doc.toResume()
+	for key := range keys {
+		dropped.add(key, !jsonResumeSections[key])
+	}
+	return resume, dropped.list(), nil
+}
+
+// toResume converts the document, recording what it cannot keep
+func (doc JSONResume) toResume() (Resume, droppedFields) {
+	dropped := droppedFields{}
+	basics := doc.Basics
+
+	resume := Resume{
+		BasicInfo: BasicInfo{
+			Name:    basics.Name,
+			Email:   basics.Email,
+			Phone:   basics.Phone,
+			Website: basics.URL,
+		},
+		Summary:      basics.Summary,
+		Experience:   []Experience{},
+		Education:    []Education{},
+		Skills:       []Skill{},
+		Certificates: []Certificate{},
+		Projects:     []Project{},
+	}
+	dropped.add("basics.label", basics.Label != "")
+	dropped.add("basics.image", basics.Image != "")
+
+	// The address parts are kept together in the one address line
+	if loc := basics.Location; loc != nil {
+		parts := []string{}
+		for _, part := range []string{loc.Address, loc.City, loc.Region, loc.PostalCode, loc.CountryCode} {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		resume.BasicInfo.Address = strings.Join(parts, ", ")
+	}
+
+	for _, profile := range basics.Profiles {
+		switch strings.ToLower(profile.Network) {
+		case "linkedin":
+			resume.BasicInfo.LinkedIn = profileURL(profile, "https://www.linkedin.com/in/")
+		case "github":
+			resume.BasicInfo.GitHub = profileURL(profile, "https://github.com/")
+		default:
+			dropped.add("basics.profiles["+profile.Network+"]", true)
+		}
+	}
+
+	for _, work := range doc.Work {
+		company := work.Name
+		if company == "" {
+			company = work.Company
+		}
+		resume.Experience = append(resume.Experience, Experience{
+			Company:     company,
+			Position:    work.Position,
+			StartDate:   work.StartDate,
+			EndDate:     work.EndDate,
+			Description: work.Summary,
+			Highlights:  nonNilStrings(work.Highlights),
+		})
+		dropped.add("work[].url", work.URL != "")
+		dropped.add("work[].location", work.Location != "")
+		dropped.add("work[].description", work.Description != "")
+	}
+
+	for _, edu := range doc.Education {
+		resume.Education = append(resume.Education, Education{
+			Institution: edu.Institution,
+			Degree:      edu.StudyType,
+			Field:       edu.Area,
+			StartDate:   edu.StartDate,
+			EndDate:     edu.EndDate,
+			GPA:         edu.Score,
+		})
+		dropped.add("education[].url", edu.URL != "")
+		dropped.add("education[].courses", len(edu.Courses) > 0)
+	}
+
+	for _, skill := range doc.Skills {
+		resume.Skills = append(resume.Skills, Skill{Name: skill.Name, Level: skill.Level})
+		dropped.add("skills[].keywords", len(skill.Keywords) > 0)
+	}
+
+	for _, cert := range doc.Certificates {
+		resume.Certificates = append(resume.Certificates, Certificate{
+			Name:      cert.Name,
+			Issuer:    cert.Issuer,
+			IssueDate: cert.Date,
+			URL:       cert.URL,
+		})
+	}
+
+	for _, project := range doc.Projects {
+		resume.Projects = append(resume.Projects, Project{
+			Name:         project.Name,
+			Description:  project.Description,
+			StartDate:    project.StartDate,
+			EndDate:      project.EndDate,
+			URL:          project.URL,
+			Technologies: nonNilStrings(project.Keywords),
+		})
+		dropped.add("projects[].highlights", len(project.Highlights) > 0)
+		dropped.add("projects[].roles", len(project.Roles) > 0)
+		dropped.add("projects[].entity", project.Entity != "")
+		dropped.add("projects[].type", project.Type != "")
+	}
+
+	dropped.add("volunteer", len(doc.Volunteer) > 0)
+	dropped.add("awards", len(doc.Awards) > 0)
+	dropped.add("publications", len(doc.Publications) > 0)
+	dropped.add("languages", len(doc.Languages) > 0)
+	dropped.add("interests", len(doc.Interests) > 0)
+	dropped.add("references", len(doc.References) > 0)
+
+	return resume, dropped
+}
+
+// ToJSONResume converts the resume to a JSON Resume document. It also returns
+// the paths of resume fields JSON Resume has no place for.
+func (r Resume) ToJSONResume() (JSONResume, []string) {
+	dropped := droppedFields{}
+	info := r.BasicInfo
+
+	doc := JSONResume{
+		Schema: JSONResumeSchema,
+		Basics: JSONResumeBasics{
+			Name:    info.Name,
+			Email:   info.Email,
+			Phone:   info.Phone,
+			URL:     info.Website,
+			Summary: r.Summary,
+		},
+	}
+	if info.Address != "" {
+		doc.Basics.Location = &JSONResumeLocation{Address: info.Address}
+	}
+	if info.LinkedIn != "" {
+		doc.Basics.Profiles = append(doc.Basics.Profiles, JSONResumeProfile{Network: "LinkedIn", Username: profileUsername(info.LinkedIn), URL: info.LinkedIn})
+	}
+	if info.GitHub != "" {
+		doc.Basics.Profiles = append(doc.Basics.Profiles, JSONResumeProfile{Network: "GitHub", Username: profileUsername(info.GitHub), URL: info.GitHub})
+	}
+
+	for _, exp := range r.Experience {
+		doc.Work = append(doc.Work, JSONResumeWork{
+			Name:       exp.Company,
+			Position:   exp.Position,
+			StartDate:  exp.StartDate,
+			EndDate:    exp.EndDate,
+			Summary:    exp.Description,
+			Highlights: exp.Highlights,
+		})
+	}
+
+	for _, edu := range r.Education {
+		doc.Education = append(doc.Education, JSONResumeEducation{
+			Institution: edu.Institution,
+			Area:        edu.Field,
+			StudyType:   edu.Degree,
+			StartDate:   edu.StartDate,
+			EndDate:     edu.EndDate,
+			Score:       edu.GPA,
+		})
+	}
+
+	for _, skill := range r.Skills {
+		doc.Skills = append(doc.Skills, JSONResumeSkill{Name: skill.Name, Level: skill.Level})
+		dropped.add("skills[].category", skill.Category != "")
+	}
+
+	for _, cert := range r.Certificates {
+		doc.Certificates = append(doc.Certificates, JSONResumeCertificate{
+			Name:   cert.Name,
+			Date:   cert.IssueDate,
+			Issuer: cert.Issuer,
+			URL:    cert.URL,
+		})
+		dropped.add("certificates[].expiryDate", cert.ExpiryDate != "")
+	}
+
+	for _, project := range r.Projects {
+		doc.Projects = append(doc.Projects, JSONResumeProject{
+			Name:        project.Name,
+			Description: project.Description,
+			Keywords:    project.Technologies,
+			StartDate:   project.StartDate,
+			EndDate:     project.EndDate,
+			URL:         project.URL,
+		})
+	}
+
+	return doc, dropped.list()
+}
+
+// profileURL returns the profile's URL, building one from the username when it has none
+func profileURL(profile JSONResumeProfile, base string) string {
+	if profile.URL != "" || profile.Username == "" {
+		return profile.URL
+	}
+	return base + profile.Username
+}
+
+// profileUsername takes the username from the last segment of a profile URL
+func profileUsername(profile string) string {
+	u, err := url.Parse(profile)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	name := path.Base(strings.TrimSuffix(u.Path, "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	return name
+}
+
+// nonNilStrings returns values, or an empty slice when it is nil
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,202 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseJSONResume(t *testing.T) {
+	// empty gives the sections a conversion always sets their empty value
+	empty := func(r Resume) Resume {
+		if r.Experience == nil {
+			r.Experience = []Experience{}
+		}
+		if r.Education == nil {
+			r.Education = []Education{}
+		}
+		if r.Skills == nil {
+			r.Skills = []Skill{}
+		}
+		if r.Certificates == nil {
+			r.Certificates = []Certificate{}
+		}
+		if r.Projects == nil {
+			r.Projects = []Project{}
+		}
+		return r
+	}
+
+	tests := []struct {
+		name        string
+		data        string
+		want        Resume
+		wantDropped []string
+		wantErr     bool
+	}{
+		{
+			name: "basics",
+			data: `{"basics": {"name": "Ada", "email": "ada@example.com", "phone": "123", "url": "https://ada.dev", "summary": "Writer",
+				"location": {"address": "12 St James's Square", "city": "London", "countryCode": "GB"},
+				"profiles": [{"network": "LinkedIn", "username": "ada"}, {"network": "GitHub", "url": "https://github.com/ada"}]}}`,
+			want: empty(Resume{
+				BasicInfo: BasicInfo{
+					Name: "Ada", Email: "ada@example.com", Phone: "123", Website: "https://ada.dev",
+					Address:  "12 St James's Square, London, GB",
+					LinkedIn: "https://www.linkedin.com/in/ada", GitHub: "https://github.com/ada",
+				},
+				Summary: "Writer",
+			}),
+			wantDropped: []string{},
+		},
+		{
+			name: "sections",
+			data: `{"work": [{"company": "Engines Ltd", "position": "Analyst", "startDate": "2019-01", "summary": "Analysis", "highlights": ["Notes"]}],
+				"education": [{"institution": "University of London", "area": "Mathematics", "studyType": "BSc", "score": "3.9"}],
+				"skills": [{"name": "Go", "level": "Expert"}],
+				"certificates": [{"name": "Operator", "issuer": "Royal Society", "date": "2020"}],
+				"projects": [{"name": "Note G", "keywords": ["Cards"], "url": "https://example.com"}]}`,
+			want: Resume{
+				Experience: []Experience{{
+					Company: "Engines Ltd", Position: "Analyst", StartDate: "2019-01",
+					Description: "Analysis", Highlights: []string{"Notes"},
+				}},
+				Education:    []Education{{Institution: "University of London", Degree: "BSc", Field: "Mathematics", GPA: "3.9"}},
+				Skills:       []Skill{{Name: "Go", Level: "Expert"}},
+				Certificates: []Certificate{{Name: "Operator", Issuer: "Royal Society", IssueDate: "2020"}},
+				Projects:     []Project{{Name: "Note G", URL: "https://example.com", Technologies: []string{"Cards"}}},
+			},
+			wantDropped: []string{},
+		},
+		{
+			name: "fields without a place are reported once",
+			data: `{"basics": {"label": "Analyst", "profiles": [{"network": "Twitter", "username": "ada"}]},
+				"work": [{"name": "A", "url": "https://a.example"}, {"name": "B", "url": "https://b.example", "location": "London"}],
+				"skills": [{"name": "Go", "keywords": ["generics"]}],
+				"awards": [{"title": "Prize"}], "languages": [], "x-custom": true}`,
+			want: empty(Resume{
+				Experience: []Experience{{Company: "A", Highlights: []string{}}, {Company: "B", Highlights: []string{}}},
+				Skills:     []Skill{{Name: "Go"}},
+			}),
+			wantDropped: []string{"awards", "basics.label", "basics.profiles[Twitter]", "skills[].keywords", "work[].location", "work[].url", "x-custom"},
+		},
+		{
+			name:    "not JSON",
+			data:    `{"basics":`,
+			wantErr: true,
+		},
+		{
+			name:    "wrong types",
+			data:    `{"work": {"name": "A"}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dropped, err := ParseJSONResume([]byte(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseJSONResume() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseJSONResume() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseJSONResume() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(dropped, tt.wantDropped) {
+				t.Errorf("ParseJSONResume() dropped = %q, want %q", dropped, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestToJSONResume(t *testing.T) {
+	tests := []struct {
+		name        string
+		resume      Resume
+		want        JSONResume
+		wantDropped []string
+	}{
+		{
+			name:        "an empty resume declares the schema",
+			resume:      Resume{},
+			want:        JSONResume{Schema: JSONResumeSchema},
+			wantDropped: []string{},
+		},
+		{
+			name: "basics and profiles",
+			resume: Resume{
+				BasicInfo: BasicInfo{
+					Name: "Ada", Email: "ada@example.com", Website: "https://ada.dev", Address: "London",
+					LinkedIn: "https://www.linkedin.com/in/ada/", GitHub: "https://github.com/ada",
+				},
+				Summary: "Writer",
+			},
+			want: JSONResume{
+				Schema: JSONResumeSchema,
+				Basics: JSONResumeBasics{
+					Name: "Ada", Email: "ada@example.com", URL: "https://ada.dev", Summary: "Writer",
+					Location: &JSONResumeLocation{Address: "London"},
+					Profiles: []JSONResumeProfile{
+						{Network: "LinkedIn", Username: "ada", URL: "https://www.linkedin.com/in/ada/"},
+						{Network: "GitHub", Username: "ada", URL: "https://github.com/ada"},
+					},
+				},
+			},
+			wantDropped: []string{},
+		},
+		{
+			name: "fields without a place are reported once",
+			resume: Resume{
+				Experience:   []Experience{{Company: "Engines Ltd", Position: "Analyst", Description: "Analysis"}},
+				Skills:       []Skill{{Name: "Go", Category: "Languages"}, {Name: "SQL", Category: "Languages"}},
+				Certificates: []Certificate{{Name: "Operator", IssueDate: "2020", ExpiryDate: "2023"}},
+				Projects:     []Project{{Name: "Note G", Technologies: []string{"Cards"}}},
+			},
+			want: JSONResume{
+				Schema:       JSONResumeSchema,
+				Work:         []JSONResumeWork{{Name: "Engines Ltd", Position: "Analyst", Summary: "Analysis"}},
+				Skills:       []JSONResumeSkill{{Name: "Go"}, {Name: "SQL"}},
+				Certificates: []JSONResumeCertificate{{Name: "Operator", Date: "2020"}},
+				Projects:     []JSONResumeProject{{Name: "Note G", Keywords: []string{"Cards"}}},
+			},
+			wantDropped: []string{"certificates[].expiryDate", "skills[].category"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dropped := tt.resume.ToJSONResume()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToJSONResume() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(dropped, tt.wantDropped) {
+				t.Errorf("ToJSONResume() dropped = %q, want %q", dropped, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestProfileUsername(t *testing.T) {
+	tests := []struct {
+		profile string
+		want    string
+	}{
+		{"https://github.com/ada", "ada"},
+		{"https://www.linkedin.com/in/ada/", "ada"},
+		{"https://github.com/", ""},
+		{"github.com/ada", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			if got := profileUsername(tt.profile); got != tt.want {
+				t.Errorf("profileUsername(%q) = %q, want %q", tt.profile, got, tt.want)
+			}
+		})
+	}
+}
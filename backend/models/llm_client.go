@@ -0,0 +1,447 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"resume.in/backend/config"
+	"resume.in/backend/utils"
+)
+
+// Message represents a chat message in the OpenAI chat completions format
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// CompletionOptions controls a single completion call. Zero values fall back
+// to the client's defaults.
+type CompletionOptions struct {
+	Model       string
+	Temperature *float64 // Unset when nil, so that 0 can be asked for
+	MaxTokens   int
+	// Timeout limits a completion call. A stream only times out when no
+	// data arrives for this long, so that long answers are not cut off.
+	Timeout time.Duration
+}
+
+// merge fills zero fields of o from defaults
+func (o CompletionOptions) merge(defaults CompletionOptions) CompletionOptions {
+	if o.Model == "" {
+		o.Model = defaults.Model
+	}
+	if o.Temperature == nil {
+		o.Temperature = defaults.Temperature
+	}
+	if o.MaxTokens == 0 {
+		o.MaxTokens = defaults.MaxTokens
+	}
+	if o.Timeout == 0 {
+		o.Timeout = defaults.Timeout
+	}
+	return o
+}
+
+// Float64 returns a pointer to v, for optional settings such as the temperature
+func Float64(v float64) *float64 {
+	return &v
+}
+
+// ErrLLMTimeout is returned when the LLM does not answer within the timeout
+var ErrLLMTimeout = errors.New("llm request timed out")
+
+// StreamHandler receives each piece of a streamed completion as it arrives.
+// Returning an error stops the stream.
+type StreamHandler func(delta string) error
+
+// LLMClient generates chat completions from a language model
+type LLMClient interface {
+	Complete(ctx context.Context, messages []Message, opts CompletionOptions) (string, error)
+	// Stream generates a completion incrementally, calling onDelta for every
+	// piece of text, and returns the assembled completion. Cancelling ctx
+	// aborts the upstream request.
+	Stream(ctx context.Context, messages []Message, opts CompletionOptions, onDelta StreamHandler) (string, error)
+}
+
+// NewLLMClient creates the LLM client selected by the configuration
+func NewLLMClient(cfg *config.Config) (LLMClient, error) {
+	defaults := CompletionOptions{
+		Model:       cfg.LLMModel,
+		Temperature: Float64(cfg.LLMTemperature),
+		MaxTokens:   cfg.LLMMaxTokens,
+		Timeout:     cfg.LLMTimeout,
+	}
+
+	switch cfg.LLMProvider {
+	case "", "openrouter":
+		return NewOpenRouterClient(cfg.OpenRouterAPIKey, defaults), nil
+	case "openai":
+		return NewOpenAICompatibleClient(cfg.LLMBaseURL, cfg.LLMAPIKey, defaults), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q", cfg.LLMProvider)
+	}
+}
+
+// ChatCompletionRequest represents a request to an OpenAI-compatible chat completions API
+type ChatCompletionRequest struct {
+	Model       string    `json:"model"`
+	Messages    []Message `json:"messages"`
+	MaxTokens   int       `json:"max_tokens,omitempty"`
+	Temperature *float64  `json:"temperature"`
+	Stream      bool      `json:"stream,omitempty"`
+}
+
+// ChatCompletionResponse represents a response from an OpenAI-compatible chat completions API
+type ChatCompletionResponse struct {
+	Choices []struct {
+		Message struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// ChatCompletionChunk is one server-sent event of a streamed chat completion
+type ChatCompletionChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// OpenAICompatibleClient talks to any server implementing the OpenAI chat
+// completions API, such as Ollama, vLLM or the llama.cpp server
+type OpenAICompatibleClient struct {
+	baseURL  string
+	apiKey   string
+	headers  map[string]string
+	defaults CompletionOptions
+	client   *http.Client
+}
+
+// NewOpenAICompatibleClient creates a client for the API at baseURL (for example http://localhost:11434/v1)
+func NewOpenAICompatibleClient(baseURL, apiKey string, defaults CompletionOptions) *OpenAICompatibleClient {
+	return &OpenAICompatibleClient{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		apiKey:   apiKey,
+		headers:  map[string]string{},
+		defaults: defaults,
+		// Timeouts are applied per call through the request context
+		client: &http.Client{},
+	}
+}
+
+// post sends a chat completions request. The caller must close the response body.
+func (c *OpenAICompatibleClient) post(ctx context.Context, messages []Message, opts CompletionOptions, stream bool) (*http.Response, error) {
+	utils.Info("Using model: %s with max_tokens: %d", opts.Model, opts.MaxTokens)
+
+	requestBody, err := json.Marshal(ChatCompletionRequest{
+		Model:       opts.Model,
+		Messages:    messages,
+		MaxTokens:   opts.MaxTokens,
+		Temperature: opts.Temperature,
+		Stream:      stream,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if stream {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	return resp, nil
+}
+
+// Complete implements LLMClient
+func (c *OpenAICompatibleClient) Complete(ctx context.Context, messages []Message, opts CompletionOptions) (string, error) {
+	opts = opts.merge(c.defaults)
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	resp, err := c.post(ctx, messages, opts, false)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var completion ChatCompletionResponse
+	if err := json.Unmarshal(body, &completion); err != nil {
+		return "", fmt.Errorf("failed to parse response (status %d): %w", resp.StatusCode, err)
+	}
+
+	if completion.Error != nil {
+		return "", fmt.Errorf("llm error: %s", completion.Error.Message)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("llm request failed with status %d", resp.StatusCode)
+	}
+
+	if len(completion.Choices) == 0 {
+		return "", fmt.Errorf("no response from LLM")
+	}
+
+	return completion.Choices[0].Message.Content, nil
+}
+
+// Stream implements LLMClient
+func (c *OpenAICompatibleClient) Stream(ctx context.Context, messages []Message, opts CompletionOptions, onDelta StreamHandler) (string, error) {
+	opts = opts.merge(c.defaults)
+
+	// The timeout restarts with every line received, so it bounds the wait
+	// for the response headers and for each chunk, not the whole answer
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var idle *time.Timer
+	if opts.Timeout > 0 {
+		idle = time.AfterFunc(opts.Timeout, cancel)
+		defer idle.Stop()
+	}
+	timedOut := func(err error) error {
+		if idle != nil && !idle.Stop() && parent.Err() == nil {
+			return fmt.Errorf("%w: no data for %s", ErrLLMTimeout, opts.Timeout)
+		}
+		return err
+	}
+
+	resp, err := c.post(ctx, messages, opts, true)
+	if err != nil {
+		return "", timedOut(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Errors are reported as a plain JSON body rather than an event stream
+		body, _ := io.ReadAll(resp.Body)
+		var completion ChatCompletionResponse
+		if json.Unmarshal(body, &completion) == nil && completion.Error != nil {
+			return "", fmt.Errorf("llm error: %s", completion.Error.Message)
+		}
+		return "", fmt.Errorf("llm request failed with status %d", resp.StatusCode)
+	}
+
+	var content strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		if idle != nil {
+			idle.Reset(opts.Timeout)
+		}
+		line := strings.TrimSpace(scanner.Text())
+
+		// Blank lines separate events, and lines starting with ':' are
+		// keep-alive comments (OpenRouter sends ": OPENROUTER PROCESSING")
+		if line == "" || strings.HasPrefix(line, ":") || !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			return content.String(), nil
+		}
+
+		var chunk ChatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return content.String(), fmt.Errorf("failed to parse stream chunk: %w", err)
+		}
+
+		if chunk.Error != nil {
+			return content.String(), fmt.Errorf("llm error: %s", chunk.Error.Message)
+		}
+
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			content.WriteString(choice.Delta.Content)
+			if err := onDelta(choice.Delta.Content); err != nil {
+				return content.String(), err
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return content.String(), fmt.Errorf("failed to read stream: %w", timedOut(err))
+	}
+
+	// Some servers close the connection without sending [DONE]
+	return content.String(), nil
+}
+
+// OpenRouterClient calls the Open Router API
+type OpenRouterClient struct {
+	*OpenAICompatibleClient
+}
+
+// NewOpenRouterClient creates an Open Router client
+func NewOpenRouterClient(apiKey string, defaults CompletionOptions) *OpenRouterClient {
+	client := NewOpenAICompatibleClient("https://openrouter.ai/api/v1", apiKey, defaults)
+	client.headers["HTTP-Referer"] = "https://resume.in" // Replace with your actual domain
+	client.headers["X-Title"] = "Resume.in Chatbot"
+	return &OpenRouterClient{OpenAICompatibleClient: client}
+}
+
+// openRouterNotConfigured is returned instead of a completion when no API key is set
+const openRouterNotConfigured = "I'm sorry, but my connection to the language model is not configured correctly. Please check your OPEN_ROUTER_API_KEY environment variable."
+
+// configured reports whether a real API key has been set
+func (c *OpenRouterClient) configured() bool {
+	if c.apiKey == "" || c.apiKey == "your_openrouter_api_key" {
+		utils.Error("OPEN_ROUTER_API_KEY is not set or is using the default value. Please set a valid API key.")
+		return false
+	}
+	return true
+}
+
+// Complete implements LLMClient
+func (c *OpenRouterClient) Complete(ctx context.Context, messages []Message, opts CompletionOptions) (string, error) {
+	if !c.configured() {
+		return openRouterNotConfigured, nil
+	}
+	return c.OpenAICompatibleClient.Complete(ctx, messages, opts)
+}
+
+// Stream implements LLMClient
+func (c *OpenRouterClient) Stream(ctx context.Context, messages []Message, opts CompletionOptions, onDelta StreamHandler) (string, error) {
+	if !c.configured() {
+		return openRouterNotConfigured, onDelta(openRouterNotConfigured)
+	}
+	return c.OpenAICompatibleClient.Stream(ctx, messages, opts, onDelta)
+}
+
+// ErrScriptExhausted is returned by FakeLLMClient when no scripted responses remain
+var ErrScriptExhausted = errors.New("fake llm: no scripted responses left")
+
+// ScriptedResponse is one canned reply of a FakeLLMClient
+type ScriptedResponse struct {
+	Content string
+	Err     error
+}
+
+// FakeLLMCall records a call made to a FakeLLMClient
+type FakeLLMCall struct {
+	Messages []Message
+	Options  CompletionOptions
+}
+
+// FakeLLMClient replays scripted responses in order and records every call.
+// It is meant for tests and offline development.
+type FakeLLMClient struct {
+	mutex     sync.Mutex
+	responses []ScriptedResponse
+	calls     []FakeLLMCall
+}
+
+// NewFakeLLMClient creates a fake client that answers with the given replies in order
+func NewFakeLLMClient(replies ...string) *FakeLLMClient {
+	f := &FakeLLMClient{}
+	for _, reply := range replies {
+		f.responses = append(f.responses, ScriptedResponse{Content: reply})
+	}
+	return f
+}
+
+// Script appends responses to the queue
+func (f *FakeLLMClient) Script(responses ...ScriptedResponse) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.responses = append(f.responses, responses...)
+}
+
+// Calls returns the calls made so far
+func (f *FakeLLMClient) Calls() []FakeLLMCall {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]FakeLLMCall(nil), f.calls...)
+}
+
+// Complete implements LLMClient
+func (f *FakeLLMClient) Complete(ctx context.Context, messages []Message, opts CompletionOptions) (string, error) {
+	return f.next(messages, opts)
+}
+
+// Stream implements LLMClient. The scripted reply is delivered word by word.
+func (f *FakeLLMClient) Stream(ctx context.Context, messages []Message, opts CompletionOptions, onDelta StreamHandler) (string, error) {
+	reply, err := f.next(messages, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var sent strings.Builder
+	for _, word := range strings.SplitAfter(reply, " ") {
+		if err := ctx.Err(); err != nil {
+			return sent.String(), err
+		}
+		if word == "" {
+			continue
+		}
+		sent.WriteString(word)
+		if err := onDelta(word); err != nil {
+			return sent.String(), err
+		}
+	}
+	return sent.String(), nil
+}
+
+// next records a call and pops the next scripted response
+func (f *FakeLLMClient) next(messages []Message, opts CompletionOptions) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.calls = append(f.calls, FakeLLMCall{
+		Messages: append([]Message(nil), messages...),
+		Options:  opts,
+	})
+
+	if len(f.responses) == 0 {
+		return "", ErrScriptExhausted
+	}
+
+	response := f.responses[0]
+	f.responses = f.responses[1:]
+	return response.Content, response.Err
+}
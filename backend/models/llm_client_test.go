@@ -0,0 +1,324 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"resume.in/backend/utils"
+)
+
+func TestMain(m *testing.M) {
+	// The clients and repositories log through the package loggers
+	utils.InitLoggers()
+	os.Exit(m.Run())
+}
+
+func TestCompletionOptionsMerge(t *testing.T) {
+	defaults := CompletionOptions{Model: "default-model", Temperature: Float64(0.7), MaxTokens: 1000, Timeout: time.Minute}
+
+	tests := []struct {
+		name string
+		opts CompletionOptions
+		want CompletionOptions
+	}{
+		{
+			name: "unset options use the defaults",
+			opts: CompletionOptions{},
+			want: defaults,
+		},
+		{
+			name: "a zero temperature is kept",
+			opts: CompletionOptions{Temperature: Float64(0)},
+			want: CompletionOptions{Model: "default-model", Temperature: Float64(0), MaxTokens: 1000, Timeout: time.Minute},
+		},
+		{
+			name: "set options override the defaults",
+			opts: CompletionOptions{Model: "other", Temperature: Float64(0.1), MaxTokens: 50, Timeout: time.Second},
+			want: CompletionOptions{Model: "other", Temperature: Float64(0.1), MaxTokens: 50, Timeout: time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.opts.merge(defaults)
+			if got.Model != tt.want.Model || got.MaxTokens != tt.want.MaxTokens || got.Timeout != tt.want.Timeout {
+				t.Errorf("merge() = %+v, want %+v", got, tt.want)
+			}
+			if got.Temperature == nil || *got.Temperature != *tt.want.Temperature {
+				t.Errorf("merge() temperature = %v, want %v", got.Temperature, *tt.want.Temperature)
+			}
+		})
+	}
+}
+
+func TestCompletionOptionsMergeWithoutDefaultTemperature(t *testing.T) {
+	got := CompletionOptions{}.merge(CompletionOptions{})
+	if got.Temperature != nil {
+		t.Errorf("merge() temperature = %v, want nil", *got.Temperature)
+	}
+}
+
+// llmServer serves a canned chat completions response
+func llmServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("request path = %s, want /chat/completions", r.URL.Path)
+		}
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenAICompatibleClientComplete(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "returns the first choice",
+			status: http.StatusOK,
+			body:   `{"choices":[{"message":{"role":"assistant","content":"Hello"}}]}`,
+			want:   "Hello",
+		},
+		{
+			name:    "reports an API error",
+			status:  http.StatusBadRequest,
+			body:    `{"error":{"message":"model not found"}}`,
+			wantErr: "llm error: model not found",
+		},
+		{
+			name:    "reports a failed status without an error body",
+			status:  http.StatusBadGateway,
+			body:    `{}`,
+			wantErr: "status 502",
+		},
+		{
+			name:    "reports a response without choices",
+			status:  http.StatusOK,
+			body:    `{"choices":[]}`,
+			wantErr: "no response from LLM",
+		},
+		{
+			name:    "reports a body that is not JSON",
+			status:  http.StatusOK,
+			body:    `<html>`,
+			wantErr: "failed to parse response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := llmServer(t, tt.status, tt.body)
+			client := NewOpenAICompatibleClient(server.URL, "", CompletionOptions{Model: "test"})
+
+			got, err := client.Complete(context.Background(), []Message{{Role: "user", Content: "Hi"}}, CompletionOptions{})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Complete() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Complete() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Complete() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAICompatibleClientSendsOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults CompletionOptions
+		opts     CompletionOptions
+		want     string // The temperature as sent
+	}{
+		{"default temperature", CompletionOptions{Temperature: Float64(0.7)}, CompletionOptions{}, "0.7"},
+		{"zero temperature", CompletionOptions{Temperature: Float64(0.7)}, CompletionOptions{Temperature: Float64(0)}, "0"},
+		{"no temperature", CompletionOptions{}, CompletionOptions{}, "null"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw map[string]json.RawMessage
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+					t.Errorf("Authorization = %q, want %q", got, "Bearer secret")
+				}
+				if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+					t.Errorf("failed to decode request: %v", err)
+				}
+				fmt.Fprint(w, `{"choices":[{"message":{"content":"ok"}}]}`)
+			}))
+			defer server.Close()
+
+			client := NewOpenAICompatibleClient(server.URL+"/", "secret", tt.defaults)
+			if _, err := client.Complete(context.Background(), nil, tt.opts); err != nil {
+				t.Fatalf("Complete() error = %v", err)
+			}
+			if got := string(raw["temperature"]); got != tt.want {
+				t.Errorf("temperature = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAICompatibleClientStream(t *testing.T) {
+	chunk := func(content string) string {
+		return fmt.Sprintf("data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", content)
+	}
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "assembles the deltas",
+			status: http.StatusOK,
+			body:   chunk("Hel") + chunk("lo") + "data: [DONE]\n\n",
+			want:   "Hello",
+		},
+		{
+			name:   "skips keep-alive comments and other fields",
+			status: http.StatusOK,
+			body:   ": OPENROUTER PROCESSING\n\nevent: message\n" + chunk("Hi") + "data: [DONE]\n\n",
+			want:   "Hi",
+		},
+		{
+			name:   "accepts a stream closed without [DONE]",
+			status: http.StatusOK,
+			body:   chunk("Hi"),
+			want:   "Hi",
+		},
+		{
+			name:    "reports an error chunk with the partial answer",
+			status:  http.StatusOK,
+			body:    chunk("Hi") + "data: {\"error\":{\"message\":\"overloaded\"}}\n\n",
+			want:    "Hi",
+			wantErr: "llm error: overloaded",
+		},
+		{
+			name:    "reports an API error before the stream",
+			status:  http.StatusUnauthorized,
+			body:    `{"error":{"message":"invalid key"}}`,
+			wantErr: "llm error: invalid key",
+		},
+		{
+			name:    "reports a malformed chunk",
+			status:  http.StatusOK,
+			body:    "data: {oops\n\n",
+			wantErr: "failed to parse stream chunk",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := llmServer(t, tt.status, tt.body)
+			client := NewOpenAICompatibleClient(server.URL, "", CompletionOptions{})
+
+			var deltas strings.Builder
+			got, err := client.Stream(context.Background(), nil, CompletionOptions{}, func(delta string) error {
+				deltas.WriteString(delta)
+				return nil
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Stream() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Stream() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Stream() = %q, want %q", got, tt.want)
+			}
+			if deltas.String() != tt.want {
+				t.Errorf("deltas = %q, want %q", deltas.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAICompatibleClientStreamTimeout(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		interval time.Duration // Pause between chunks
+		stall    time.Duration // Pause before [DONE]
+		wantErr  error
+	}{
+		{"a long stream that keeps sending is not cut off", timeout / 2, 0, nil},
+		{"a stalled stream times out", 0, 3 * timeout, ErrLLMTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for i := 0; i < 5; i++ {
+					fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"x\"}}]}\n\n")
+					w.(http.Flusher).Flush()
+					time.Sleep(tt.interval)
+				}
+				select {
+				case <-time.After(tt.stall):
+				case <-r.Context().Done():
+					return
+				}
+				fmt.Fprint(w, "data: [DONE]\n\n")
+			}))
+			defer server.Close()
+
+			client := NewOpenAICompatibleClient(server.URL, "", CompletionOptions{Timeout: timeout})
+			got, err := client.Stream(context.Background(), nil, CompletionOptions{}, func(string) error { return nil })
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Stream() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != "xxxxx" {
+				t.Errorf("Stream() = %q, want %q", got, "xxxxx")
+			}
+		})
+	}
+}
+
+func TestFakeLLMClient(t *testing.T) {
+	fake := NewFakeLLMClient("first answer")
+	fake.Script(ScriptedResponse{Err: errors.New("boom")})
+
+	got, err := fake.Complete(context.Background(), []Message{{Role: "user", Content: "Q"}}, CompletionOptions{Temperature: Float64(0)})
+	if err != nil || got != "first answer" {
+		t.Fatalf("Complete() = %q, %v, want %q", got, err, "first answer")
+	}
+	if _, err := fake.Stream(context.Background(), nil, CompletionOptions{}, func(string) error { return nil }); err == nil || err.Error() != "boom" {
+		t.Fatalf("Stream() error = %v, want boom", err)
+	}
+	if _, err := fake.Complete(context.Background(), nil, CompletionOptions{}); !errors.Is(err, ErrScriptExhausted) {
+		t.Fatalf("Complete() error = %v, want %v", err, ErrScriptExhausted)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("Calls() = %d calls, want 3", len(calls))
+	}
+	if temperature := calls[0].Options.Temperature; temperature == nil || *temperature != 0 {
+		t.Errorf("first call temperature = %v, want 0", temperature)
+	}
+}
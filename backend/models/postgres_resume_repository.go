@@ -1,10 +1,12 @@
 package models
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -13,38 +15,28 @@ type PostgresResumeRepository struct {
 	db *sqlx.DB
 }
 
-// NewPostgresResumeRepository creates a new repository with the given database connection
+// NewPostgresResumeRepository creates a new repository with the given
+// database connection. Its tables are created by the migrations.
 func NewPostgresResumeRepository(db *sqlx.DB) (*PostgresResumeRepository, error) {
-	repo := &PostgresResumeRepository{
+	return &PostgresResumeRepository{
 		db: db,
-	}
-
-	// Initialize database tables
-	err := repo.initTables()
-	if err != nil {
-		return nil, err
-	}
-
-	return repo, nil
+	}, nil
 }
 
-// initTables creates the necessary tables if they don't exist
-func (r *PostgresResumeRepository) initTables() error {
-	query := `
-    CREATE TABLE IF NOT EXISTS resumes (
-        id VARCHAR(100) PRIMARY KEY,
-        data JSONB NOT NULL
-    );
-    `
-
-	_, err := r.db.Exec(query)
-	return err
+// scanResume decodes a resume row and takes the owner from its column
+func scanResume(ownerID sql.NullString, data []byte) (Resume, error) {
+	var resume Resume
+	if err := json.Unmarshal(data, &resume); err != nil {
+		return Resume{}, fmt.Errorf("failed to parse resume data: %v", err)
+	}
+	resume.OwnerID = ownerID.String
+	return resume, nil
 }
 
-// FindAll returns all resumes
-func (r *PostgresResumeRepository) FindAll() []Resume {
-	query := `SELECT data FROM resumes;`
-	rows, err := r.db.Queryx(query)
+// FindAll returns all resumes owned by the user
+func (r *PostgresResumeRepository) FindAll(ownerID string) []Resume {
+	query := `SELECT owner_id, data FROM resumes WHERE owner_id = $1;`
+	rows, err := r.db.Queryx(query, ownerID)
 	if err != nil {
 		return []Resume{}
 	}
@@ -52,13 +44,14 @@ func (r *PostgresResumeRepository) FindAll() []Resume {
 
 	var resumes []Resume
 	for rows.Next() {
+		var owner sql.NullString
 		var data []byte
-		if err := rows.Scan(&data); err != nil {
+		if err := rows.Scan(&owner, &data); err != nil {
 			continue
 		}
 
-		var resume Resume
-		if err := json.Unmarshal(data, &resume); err != nil {
+		resume, err := scanResume(owner, data)
+		if err != nil {
 			continue
 		}
 
@@ -68,35 +61,44 @@ func (r *PostgresResumeRepository) FindAll() []Resume {
 	return resumes
 }
 
-// FindByID returns a resume by its ID
-func (r *PostgresResumeRepository) FindByID(id string) (Resume, error) {
-	query := `SELECT data FROM resumes WHERE id = $1;`
+// FindByID returns a resume by its ID. It returns ErrResumeForbidden if the
+// resume belongs to another user.
+func (r *PostgresResumeRepository) FindByID(ownerID, id string) (Resume, error) {
+	query := `SELECT owner_id, data FROM resumes WHERE id = $1;`
+	var owner sql.NullString
 	var data []byte
-	err := r.db.QueryRowx(query, id).Scan(&data)
+	err := r.db.QueryRowx(query, id).Scan(&owner, &data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Resume{}, ErrResumeNotFound
+	}
 	if err != nil {
-		return Resume{}, errors.New("resume not found")
+		return Resume{}, fmt.Errorf("failed to find resume: %w", err)
 	}
 
-	var resume Resume
-	if err := json.Unmarshal(data, &resume); err != nil {
-		return Resume{}, fmt.Errorf("failed to parse resume data: %v", err)
+	if owner.String != ownerID {
+		return Resume{}, ErrResumeForbidden
 	}
 
-	return resume, nil
+	return scanResume(owner, data)
 }
 
-// Create adds a new resume
-func (r *PostgresResumeRepository) Create(resume Resume) (Resume, error) {
+// Create adds a new resume owned by the user
+func (r *PostgresResumeRepository) Create(ownerID string, resume Resume) (Resume, error) {
 	if resume.ID == "" {
 		return Resume{}, errors.New("resume ID is required")
 	}
 
 	// Check if resume already exists
-	_, err := r.FindByID(resume.ID)
-	if err == nil {
+	var exists bool
+	if err := r.db.QueryRowx(`SELECT EXISTS(SELECT 1 FROM resumes WHERE id = $1);`, resume.ID).Scan(&exists); err != nil {
+		return Resume{}, fmt.Errorf("failed to check resume: %v", err)
+	}
+	if exists {
 		return Resume{}, errors.New("resume with this ID already exists")
 	}
 
+	resume.OwnerID = ownerID
+
 	// Convert resume to JSON
 	resumeJSON, err := json.Marshal(resume)
 	if err != nil {
@@ -104,8 +106,8 @@ func (r *PostgresResumeRepository) Create(resume Resume) (Resume, error) {
 	}
 
 	// Insert into database
-	query := `INSERT INTO resumes (id, data) VALUES ($1, $2);`
-	_, err = r.db.Exec(query, resume.ID, resumeJSON)
+	query := `INSERT INTO resumes (id, owner_id, data) VALUES ($1, NULLIF($2, ''), $3);`
+	_, err = r.db.Exec(query, resume.ID, ownerID, resumeJSON)
 	if err != nil {
 		return Resume{}, fmt.Errorf("failed to insert resume: %v", err)
 	}
@@ -114,15 +116,15 @@ func (r *PostgresResumeRepository) Create(resume Resume) (Resume, error) {
 }
 
 // Update modifies an existing resume
-func (r *PostgresResumeRepository) Update(id string, resume Resume) (Resume, error) {
-	// Check if resume exists
-	_, err := r.FindByID(id)
-	if err != nil {
-		return Resume{}, errors.New("resume not found")
+func (r *PostgresResumeRepository) Update(ownerID, id string, resume Resume) (Resume, error) {
+	// Check if resume exists and belongs to the user
+	if _, err := r.FindByID(ownerID, id); err != nil {
+		return Resume{}, err
 	}
 
 	// Set ID to the path parameter value
 	resume.ID = id
+	resume.OwnerID = ownerID
 
 	// Convert resume to JSON
 	resumeJSON, err := json.Marshal(resume)
@@ -131,8 +133,8 @@ func (r *PostgresResumeRepository) Update(id string, resume Resume) (Resume, err
 	}
 
 	// Update database
-	query := `UPDATE resumes SET data = $1 WHERE id = $2;`
-	_, err = r.db.Exec(query, resumeJSON, id)
+	query := `UPDATE resumes SET data = $1 WHERE id = $2 AND owner_id = $3;`
+	_, err = r.db.Exec(query, resumeJSON, id, ownerID)
 	if err != nil {
 		return Resume{}, fmt.Errorf("failed to update resume: %v", err)
 	}
@@ -141,16 +143,15 @@ func (r *PostgresResumeRepository) Update(id string, resume Resume) (Resume, err
 }
 
 // Delete removes a resume
-func (r *PostgresResumeRepository) Delete(id string) error {
-	// Check if resume exists
-	_, err := r.FindByID(id)
-	if err != nil {
-		return errors.New("resume not found")
+func (r *PostgresResumeRepository) Delete(ownerID, id string) error {
+	// Check if resume exists and belongs to the user
+	if _, err := r.FindByID(ownerID, id); err != nil {
+		return err
 	}
 
 	// Delete from database
-	query := `DELETE FROM resumes WHERE id = $1;`
-	_, err = r.db.Exec(query, id)
+	query := `DELETE FROM resumes WHERE id = $1 AND owner_id = $2;`
+	_, err := r.db.Exec(query, id, ownerID)
 	if err != nil {
 		return fmt.Errorf("failed to delete resume: %v", err)
 	}
@@ -158,10 +159,10 @@ func (r *PostgresResumeRepository) Delete(id string) error {
 	return nil
 }
 
-// GetAllSkills returns all skills from all resumes
-func (r *PostgresResumeRepository) GetAllSkills() []Skill {
+// GetAllSkills returns all skills from the user's resumes
+func (r *PostgresResumeRepository) GetAllSkills(ownerID string) []Skill {
 	var allSkills []Skill
-	resumes := r.FindAll()
+	resumes := r.FindAll(ownerID)
 
 	for _, resume := range resumes {
 		allSkills = append(allSkills, resume.Skills...)
@@ -170,10 +171,10 @@ func (r *PostgresResumeRepository) GetAllSkills() []Skill {
 	return allSkills
 }
 
-// GetAllExperience returns all experiences from all resumes
-func (r *PostgresResumeRepository) GetAllExperience() []Experience {
+// GetAllExperience returns all experiences from the user's resumes
+func (r *PostgresResumeRepository) GetAllExperience(ownerID string) []Experience {
 	var allExperience []Experience
-	resumes := r.FindAll()
+	resumes := r.FindAll(ownerID)
 
 	for _, resume := range resumes {
 		allExperience = append(allExperience, resume.Experience...)
@@ -182,66 +183,79 @@ func (r *PostgresResumeRepository) GetAllExperience() []Experience {
 	return allExperience
 }
 
-// InitDemoData adds sample data to the repository
-func (r *PostgresResumeRepository) InitDemoData() error {
-	// Check if we already have data
-	resumes := r.FindAll()
-	if len(resumes) > 0 {
-		return nil
-	}
-
-	sampleResume := Resume{
-		ID: "sample",
-		BasicInfo: BasicInfo{
-			Name:     "John Doe",
-			Email:    "john.doe@example.com",
-			Phone:    "+1 (123) 456-7890",
-			Address:  "123 Main St, City, Country",
-			Website:  "https://johndoe.com",
-			LinkedIn: "https://linkedin.com/in/johndoe",
-			GitHub:   "https://github.com/johndoe",
-		},
-		Summary: "Software engineer with 5 years of experience in web development",
-		Experience: []Experience{
-			{
-				Company:     "Tech Company",
-				Position:    "Senior Developer",
-				StartDate:   "2021-01-01",
-				EndDate:     "Present",
-				Description: "Full-stack development with React and Go",
-				Highlights: []string{
-					"Improved application performance by 30%",
-					"Led a team of 5 developers",
-				},
-			},
-		},
-		Education: []Education{
-			{
-				Institution: "University of Example",
-				Degree:      "Bachelor's",
-				Field:       "Computer Science",
-				StartDate:   "2014-09-01",
-				EndDate:     "2018-06-30",
-				GPA:         "3.8",
-			},
-		},
-		Skills: []Skill{
-			{Name: "Go", Level: "Expert", Category: "Programming Languages"},
-			{Name: "React", Level: "Advanced", Category: "Frontend"},
-			{Name: "Docker", Level: "Intermediate", Category: "DevOps"},
-		},
-		Projects: []Project{
-			{
-				Name:        "Resume Builder",
-				Description: "A web application to create and manage resumes",
-				StartDate:   "2022-03-01",
-				EndDate:     "2022-06-01",
-				URL:         "https://github.com/johndoe/resume-builder",
-				Technologies: []string{"Go", "React", "PostgreSQL"},
-			},
-		},
-	}
-
-	_, err := r.Create(sampleResume)
-	return err
-} 
\ No newline at end of file
+// templateColumns are the custom_templates columns in CustomTemplate field order
+const templateColumns = `id, owner_id, name, format, content, created_at, updated_at`
+
+// scanTemplate reads a custom_templates row selected with templateColumns
+func scanTemplate(row interface{ Scan(...interface{}) error }) (CustomTemplate, error) {
+	var t CustomTemplate
+	err := row.Scan(&t.ID, &t.OwnerID, &t.Name, &t.Format, &t.Content, &t.CreatedAt, &t.UpdatedAt)
+	return t, err
+}
+
+// FindTemplates returns the user's custom templates sorted by format and name
+func (r *PostgresResumeRepository) FindTemplates(ownerID string) []CustomTemplate {
+	query := `SELECT ` + templateColumns + ` FROM custom_templates WHERE owner_id = $1 ORDER BY format, name;`
+	rows, err := r.db.Queryx(query, ownerID)
+	if err != nil {
+		return []CustomTemplate{}
+	}
+	defer rows.Close()
+
+	templates := []CustomTemplate{}
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			continue
+		}
+		templates = append(templates, template)
+	}
+
+	return templates
+}
+
+// FindTemplate returns the user's custom template with the given format and name
+func (r *PostgresResumeRepository) FindTemplate(ownerID, format, name string) (CustomTemplate, error) {
+	query := `SELECT ` + templateColumns + ` FROM custom_templates WHERE owner_id = $1 AND format = $2 AND name = $3;`
+	template, err := scanTemplate(r.db.QueryRowx(query, ownerID, format, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return CustomTemplate{}, ErrTemplateNotFound
+	}
+	if err != nil {
+		return CustomTemplate{}, fmt.Errorf("failed to get template: %v", err)
+	}
+	return template, nil
+}
+
+// SaveTemplate creates a custom template or replaces the one with the same format and name
+func (r *PostgresResumeRepository) SaveTemplate(ownerID string, template CustomTemplate) (CustomTemplate, bool, error) {
+	// xmax is only zero for rows the statement inserted rather than updated
+	query := `
+		INSERT INTO custom_templates (id, owner_id, name, format, content)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (owner_id, format, name)
+		DO UPDATE SET content = EXCLUDED.content, updated_at = NOW()
+		RETURNING ` + templateColumns + `, xmax = 0;`
+
+	var saved CustomTemplate
+	var created bool
+	err := r.db.QueryRowx(query, uuid.New().String(), ownerID, template.Name, template.Format, template.Content).Scan(
+		&saved.ID, &saved.OwnerID, &saved.Name, &saved.Format, &saved.Content, &saved.CreatedAt, &saved.UpdatedAt, &created)
+	if err != nil {
+		return CustomTemplate{}, false, fmt.Errorf("failed to save template: %v", err)
+	}
+
+	return saved, created, nil
+}
+
+// DeleteTemplate removes one of the user's custom templates
+func (r *PostgresResumeRepository) DeleteTemplate(ownerID, id string) error {
+	result, err := r.db.Exec(`DELETE FROM custom_templates WHERE id = $1 AND owner_id = $2;`, id, ownerID)
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %v", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package models
 // Resume represents the resume data structure
 type Resume struct {
 	ID           string       `json:"id"`
+	OwnerID      string       `json:"ownerId,omitempty"` // ID of the user who owns the resume
 	BasicInfo    BasicInfo    `json:"basicInfo"`
 	Summary      string       `json:"summary"`
 	Experience   []Experience `json:"experience"`
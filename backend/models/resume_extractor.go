@@ -0,0 +1,261 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"resume.in/backend/utils"
+)
+
+// ErrNoResumeJSON is returned when an LLM reply contains no JSON object
+var ErrNoResumeJSON = errors.New("reply does not contain a JSON object")
+
+// resumeSchema describes the JSON the LLM must produce. It mirrors the
+// json tags of Resume, without the fields the server assigns itself.
+const resumeSchema = `{
+  "basicInfo": {"name": "", "email": "", "phone": "", "address": "", "website": "", "linkedin": "", "github": ""},
+  "summary": "",
+  "experience": [{"company": "", "position": "", "startDate": "", "endDate": "", "description": "", "highlights": [""]}],
+  "education": [{"institution": "", "degree": "", "field": "", "startDate": "", "endDate": "", "gpa": ""}],
+  "skills": [{"name": "", "level": "", "category": ""}],
+  "certificates": [{"name": "", "issuer": "", "issueDate": "", "expiryDate": "", "url": ""}],
+  "projects": [{"name": "", "description": "", "startDate": "", "endDate": "", "url": "", "technologies": [""]}]
+}`
+
+// resumeExtractionPrompt is the system prompt for resume extraction
+const resumeExtractionPrompt = `You extract resume data from a conversation between a user and an assistant.
+
+Reply with a single JSON object and nothing else, using exactly this structure:
+` + resumeSchema + `
+
+Rules:
+- Only use facts the user stated about themselves. Never invent, guess or use placeholder values.
+- Leave a string empty ("") when the conversation does not mention it.
+- Leave an array empty ([]) when the conversation has no entries for it. Do not add example entries.
+- Every experience needs a company or a position, every education entry an institution, and every skill, certificate and project a name.
+- Use "Present" as endDate only if the user said the role or study is ongoing.
+- Write the summary only from what the user said; leave it empty if there is not enough to go on.`
+
+// documentExtractionPrompt is the system prompt for extracting a resume from
+// the text of an uploaded resume file
+const documentExtractionPrompt = `You extract resume data from the text of a resume document. The text was
+extracted from a PDF, so columns may be interleaved, bullets may show up as stray characters and
+words may be split across lines.
+
+Reply with a single JSON object and nothing else, using exactly this structure:
+` + resumeSchema + `
+
+Rules:
+- Only use facts stated in the document. Never invent, guess or use placeholder values.
+- Leave a string empty ("") when the document does not mention it.
+- Leave an array empty ([]) when the document has no entries for it. Do not add example entries.
+- Every experience needs a company or a position, every education entry an institution, and every skill, certificate and project a name.
+- Copy dates as written. Use "Present" as endDate when the document marks a role or study as ongoing.
+- Put bullet points of an experience in highlights, and other prose about it in description.
+- Use the document's own summary or profile section as the summary; leave it empty if there is none.`
+
+// ResumeExtractor turns a chat conversation or resume document into structured
+// resume data using an LLM
+type ResumeExtractor struct {
+	llm         LLMClient
+	maxAttempts int
+}
+
+// NewResumeExtractor creates an extractor that retries malformed replies up to two times
+func NewResumeExtractor(llm LLMClient) *ResumeExtractor {
+	return &ResumeExtractor{llm: llm, maxAttempts: 3}
+}
+
+// Extract asks the LLM for the resume described by the conversation. Replies
+// that are not valid JSON for Resume are sent back to the model with the
+// validation error until it gets them right or the attempts run out.
+// Sections the conversation says nothing about are left empty.
+func (e *ResumeExtractor) Extract(ctx context.Context, messages []ChatMessage) (Resume, error) {
+	var transcript strings.Builder
+	for _, msg := range messages {
+		if msg.Role != "user" && msg.Role != "assistant" {
+			continue
+		}
+		fmt.Fprintf(&transcript, "%s: %s\n\n", msg.Role, msg.Content)
+	}
+
+	return e.extract(ctx, resumeExtractionPrompt, "Conversation:\n\n"+transcript.String())
+}
+
+// ExtractDocument asks the LLM for the resume in the text of a resume file,
+// retrying invalid replies the same way as Extract
+func (e *ResumeExtractor) ExtractDocument(ctx context.Context, text string) (Resume, error) {
+	return e.extract(ctx, documentExtractionPrompt, "Resume document:\n\n"+text)
+}
+
+// extract runs the prompt and retry loop shared by Extract and ExtractDocument
+func (e *ResumeExtractor) extract(ctx context.Context, system, input string) (Resume, error) {
+	prompt := []Message{
+		{Role: "system", Content: system},
+		{Role: "user", Content: input},
+	}
+
+	// Keep the model as deterministic as the provider allows
+	opts := CompletionOptions{Temperature: Float64(0.1)}
+
+	var lastErr error
+	for attempt := 1; attempt <= e.maxAttempts; attempt++ {
+		reply, err := e.llm.Complete(ctx, prompt, opts)
+		if err != nil {
+			// Transport failures are not the model's fault, so don't retry them
+			return Resume{}, fmt.Errorf("failed to get resume data from LLM: %w", err)
+		}
+
+		resume, err := parseExtractedResume(reply)
+		if err == nil {
+			return resume, nil
+		}
+
+		utils.Warning("Resume extraction attempt %d/%d returned invalid data: %v", attempt, e.maxAttempts, err)
+		lastErr = err
+
+		prompt = append(prompt,
+			Message{Role: "assistant", Content: reply},
+			Message{Role: "user", Content: fmt.Sprintf("That reply was invalid: %v. Reply again with only the corrected JSON object.", err)},
+		)
+	}
+
+	return Resume{}, fmt.Errorf("resume extraction failed after %d attempts: %w", e.maxAttempts, lastErr)
+}
+
+// parseExtractedResume decodes and validates an LLM reply
+func parseExtractedResume(reply string) (Resume, error) {
+	// Models often wrap JSON in a markdown code fence or add a sentence around it
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return Resume{}, ErrNoResumeJSON
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(reply[start : end+1])))
+	decoder.DisallowUnknownFields()
+
+	var resume Resume
+	if err := decoder.Decode(&resume); err != nil {
+		return Resume{}, fmt.Errorf("invalid resume JSON: %w", err)
+	}
+
+	// The server owns these
+	resume.ID = ""
+	resume.OwnerID = ""
+
+	normalizeExtractedResume(&resume)
+
+	if err := validateExtractedResume(resume); err != nil {
+		return Resume{}, err
+	}
+
+	return resume, nil
+}
+
+// normalizeExtractedResume trims whitespace, drops blank entries and makes
+// empty sections empty arrays
+func normalizeExtractedResume(resume *Resume) {
+	trim := func(fields ...*string) {
+		for _, field := range fields {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+	trimList := func(items []string) []string {
+		result := []string{}
+		for _, item := range items {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+		return result
+	}
+
+	info := &resume.BasicInfo
+	trim(&info.Name, &info.Email, &info.Phone, &info.Address, &info.Website, &info.LinkedIn, &info.GitHub, &resume.Summary)
+
+	// Entries with every field blank are copies of the schema, not data, so drop them
+	experience := []Experience{}
+	for _, exp := range resume.Experience {
+		trim(&exp.Company, &exp.Position, &exp.StartDate, &exp.EndDate, &exp.Description)
+		exp.Highlights = trimList(exp.Highlights)
+		if exp.Company+exp.Position+exp.StartDate+exp.EndDate+exp.Description != "" || len(exp.Highlights) > 0 {
+			experience = append(experience, exp)
+		}
+	}
+	resume.Experience = experience
+
+	education := []Education{}
+	for _, edu := range resume.Education {
+		trim(&edu.Institution, &edu.Degree, &edu.Field, &edu.StartDate, &edu.EndDate, &edu.GPA)
+		if edu.Institution+edu.Degree+edu.Field+edu.StartDate+edu.EndDate+edu.GPA != "" {
+			education = append(education, edu)
+		}
+	}
+	resume.Education = education
+
+	skills := []Skill{}
+	for _, skill := range resume.Skills {
+		trim(&skill.Name, &skill.Level, &skill.Category)
+		if skill.Name+skill.Level+skill.Category != "" {
+			skills = append(skills, skill)
+		}
+	}
+	resume.Skills = skills
+
+	certificates := []Certificate{}
+	for _, cert := range resume.Certificates {
+		trim(&cert.Name, &cert.Issuer, &cert.IssueDate, &cert.ExpiryDate, &cert.URL)
+		if cert.Name+cert.Issuer+cert.IssueDate+cert.ExpiryDate+cert.URL != "" {
+			certificates = append(certificates, cert)
+		}
+	}
+	resume.Certificates = certificates
+
+	projects := []Project{}
+	for _, project := range resume.Projects {
+		trim(&project.Name, &project.Description, &project.StartDate, &project.EndDate, &project.URL)
+		project.Technologies = trimList(project.Technologies)
+		if project.Name+project.Description+project.StartDate+project.EndDate+project.URL != "" || len(project.Technologies) > 0 {
+			projects = append(projects, project)
+		}
+	}
+	resume.Projects = projects
+}
+
+// validateExtractedResume checks that every entry has the fields that identify it
+func validateExtractedResume(resume Resume) error {
+	if email := resume.BasicInfo.Email; email != "" && !strings.Contains(email, "@") {
+		return fmt.Errorf("basicInfo.email %q is not an email address", email)
+	}
+	for i, exp := range resume.Experience {
+		if exp.Company == "" && exp.Position == "" {
+			return fmt.Errorf("experience[%d] needs a company or a position", i)
+		}
+	}
+	for i, edu := range resume.Education {
+		if edu.Institution == "" {
+			return fmt.Errorf("education[%d] needs an institution", i)
+		}
+	}
+	for i, skill := range resume.Skills {
+		if skill.Name == "" {
+			return fmt.Errorf("skills[%d] needs a name", i)
+		}
+	}
+	for i, cert := range resume.Certificates {
+		if cert.Name == "" {
+			return fmt.Errorf("certificates[%d] needs a name", i)
+		}
+	}
+	for i, project := range resume.Projects {
+		if project.Name == "" {
+			return fmt.Errorf("projects[%d] needs a name", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseExtractedResume(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		want    Resume
+		wantErr string
+	}{
+		{
+			name:  "plain JSON",
+			reply: `{"basicInfo": {"name": "Ada Lovelace", "email": "ada@example.com"}, "summary": "Mathematician"}`,
+			want: Resume{
+				BasicInfo:  BasicInfo{Name: "Ada Lovelace", Email: "ada@example.com"},
+				Summary:    "Mathematician",
+				Experience: []Experience{}, Education: []Education{}, Skills: []Skill{}, Certificates: []Certificate{}, Projects: []Project{},
+			},
+		},
+		{
+			name:  "JSON in a code fence with text around it",
+			reply: "Here is the resume:\n```json\n{\"skills\": [{\"name\": \"Go\"}]}\n```\nLet me know!",
+			want: Resume{
+				Experience: []Experience{}, Education: []Education{}, Skills: []Skill{{Name: "Go"}}, Certificates: []Certificate{}, Projects: []Project{},
+			},
+		},
+		{
+			name:  "fields are trimmed, blank entries dropped and server fields cleared",
+			reply: `{"id": "abc", "ownerId": "someone", "basicInfo": {"name": "  Ada  "}, "experience": [{"company": " Analytical Engines ", "highlights": [" Built it ", " "]}, {"company": "", "position": ""}], "projects": [{"name": "Notes", "technologies": ["", "Paper"]}]}`,
+			want: Resume{
+				BasicInfo:    BasicInfo{Name: "Ada"},
+				Experience:   []Experience{{Company: "Analytical Engines", Highlights: []string{"Built it"}}},
+				Education:    []Education{},
+				Skills:       []Skill{},
+				Certificates: []Certificate{},
+				Projects:     []Project{{Name: "Notes", Technologies: []string{"Paper"}}},
+			},
+		},
+		{
+			name:    "no JSON",
+			reply:   "I could not find a resume in this conversation.",
+			wantErr: ErrNoResumeJSON.Error(),
+		},
+		{
+			name:    "unknown fields",
+			reply:   `{"basicInfo": {"fullName": "Ada"}}`,
+			wantErr: "invalid resume JSON",
+		},
+		{
+			name:    "wrong types",
+			reply:   `{"skills": "Go, SQL"}`,
+			wantErr: "invalid resume JSON",
+		},
+		{
+			name:    "an email address without @",
+			reply:   `{"basicInfo": {"email": "ada at example.com"}}`,
+			wantErr: "basicInfo.email",
+		},
+		{
+			name:    "an experience without company or position",
+			reply:   `{"experience": [{"description": "Did things"}]}`,
+			wantErr: "experience[0] needs a company or a position",
+		},
+		{
+			name:    "education without an institution",
+			reply:   `{"education": [{"degree": "BSc"}]}`,
+			wantErr: "education[0] needs an institution",
+		},
+		{
+			name:    "a skill without a name",
+			reply:   `{"skills": [{"name": "Go"}, {"level": "Expert"}]}`,
+			wantErr: "skills[1] needs a name",
+		},
+		{
+			name:    "a certificate without a name",
+			reply:   `{"certificates": [{"issuer": "AWS"}]}`,
+			wantErr: "certificates[0] needs a name",
+		},
+		{
+			name:    "a project without a name",
+			reply:   `{"projects": [{"url": "https://example.com"}]}`,
+			wantErr: "projects[0] needs a name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExtractedResume(tt.reply)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseExtractedResume() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseExtractedResume() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseExtractedResume() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResumeExtractorExtract(t *testing.T) {
+	valid := `{"basicInfo": {"name": "Ada"}}`
+	invalid := `{"skills": [{"level": "Expert"}]}`
+
+	tests := []struct {
+		name      string
+		responses []ScriptedResponse
+		wantName  string
+		wantCalls int
+		wantErr   string
+	}{
+		{
+			name:      "a valid first reply",
+			responses: []ScriptedResponse{{Content: valid}},
+			wantName:  "Ada",
+			wantCalls: 1,
+		},
+		{
+			name:      "an invalid reply is retried with the error",
+			responses: []ScriptedResponse{{Content: invalid}, {Content: valid}},
+			wantName:  "Ada",
+			wantCalls: 2,
+		},
+		{
+			name:      "gives up after three attempts",
+			responses: []ScriptedResponse{{Content: invalid}, {Content: "no JSON"}, {Content: invalid}, {Content: valid}},
+			wantCalls: 3,
+			wantErr:   "failed after 3 attempts",
+		},
+		{
+			name:      "transport errors are not retried",
+			responses: []ScriptedResponse{{Err: errors.New("connection refused")}, {Content: valid}},
+			wantCalls: 1,
+			wantErr:   "connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := NewFakeLLMClient()
+			llm.Script(tt.responses...)
+
+			messages := []ChatMessage{{Role: "user", Content: "I'm Ada."}}
+			resume, err := NewResumeExtractor(llm).Extract(context.Background(), messages)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Extract() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			} else if resume.BasicInfo.Name != tt.wantName {
+				t.Errorf("Extract() name = %q, want %q", resume.BasicInfo.Name, tt.wantName)
+			}
+
+			calls := llm.Calls()
+			if len(calls) != tt.wantCalls {
+				t.Fatalf("Extract() made %d calls, want %d", len(calls), tt.wantCalls)
+			}
+			// Every retry sends back the invalid reply and what was wrong with it
+			for i := 1; i < len(calls); i++ {
+				prompt := calls[i].Messages
+				if len(prompt) != 2+2*i || !strings.Contains(prompt[len(prompt)-1].Content, "That reply was invalid") {
+					t.Errorf("retry %d prompt = %+v, want the previous replies and their errors", i, prompt)
+				}
+			}
+		})
+	}
+}
@@ -2,59 +2,91 @@ package models
 
 import (
 	"errors"
+	"sort"
 	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrResumeNotFound is returned when no resume exists with the given ID
+	ErrResumeNotFound = errors.New("resume not found")
+	// ErrResumeForbidden is returned when a resume exists but belongs to another user
+	ErrResumeForbidden = errors.New("resume belongs to another user")
 )
 
-// ResumeRepository defines the interface for resume data operations
+// ResumeRepository defines the interface for resume data operations.
+// Every operation is scoped to the owning user's ID.
 type ResumeRepository interface {
-	FindAll() []Resume
-	FindByID(id string) (Resume, error)
-	Create(resume Resume) (Resume, error)
-	Update(id string, resume Resume) (Resume, error)
-	Delete(id string) error
-	GetAllSkills() []Skill
-	GetAllExperience() []Experience
+	FindAll(ownerID string) []Resume
+	FindByID(ownerID, id string) (Resume, error)
+	Create(ownerID string, resume Resume) (Resume, error)
+	Update(ownerID, id string, resume Resume) (Resume, error)
+	Delete(ownerID, id string) error
+	GetAllSkills(ownerID string) []Skill
+	GetAllExperience(ownerID string) []Experience
+
+	// Custom export templates, unique per user by format and name
+	FindTemplates(ownerID string) []CustomTemplate
+	FindTemplate(ownerID, format, name string) (CustomTemplate, error)
+	// SaveTemplate creates the template, or replaces the content of the user's
+	// template with the same format and name. It reports whether it created one.
+	SaveTemplate(ownerID string, template CustomTemplate) (CustomTemplate, bool, error)
+	DeleteTemplate(ownerID, id string) error
 }
 
 // InMemoryResumeRepository implements ResumeRepository with an in-memory map
 type InMemoryResumeRepository struct {
-	resumes map[string]Resume
-	mutex   sync.RWMutex
+	resumes   map[string]Resume
+	templates map[string]CustomTemplate
+	mutex     sync.RWMutex
 }
 
 // NewInMemoryResumeRepository creates a new in-memory resume repository
 func NewInMemoryResumeRepository() *InMemoryResumeRepository {
 	return &InMemoryResumeRepository{
-		resumes: make(map[string]Resume),
+		resumes:   make(map[string]Resume),
+		templates: make(map[string]CustomTemplate),
 	}
 }
 
-// FindAll returns all resumes
-func (r *InMemoryResumeRepository) FindAll() []Resume {
+// FindAll returns all resumes owned by the user
+func (r *InMemoryResumeRepository) FindAll(ownerID string) []Resume {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
 	var result []Resume
 	for _, resume := range r.resumes {
-		result = append(result, resume)
+		if resume.OwnerID == ownerID {
+			result = append(result, resume)
+		}
 	}
 	return result
 }
 
 // FindByID returns a resume by its ID
-func (r *InMemoryResumeRepository) FindByID(id string) (Resume, error) {
+func (r *InMemoryResumeRepository) FindByID(ownerID, id string) (Resume, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
+	return r.findOwned(ownerID, id)
+}
+
+// findOwned looks up a resume and checks its owner. The caller must hold the lock.
+func (r *InMemoryResumeRepository) findOwned(ownerID, id string) (Resume, error) {
 	resume, exists := r.resumes[id]
 	if !exists {
-		return Resume{}, errors.New("resume not found")
+		return Resume{}, ErrResumeNotFound
+	}
+	if resume.OwnerID != ownerID {
+		return Resume{}, ErrResumeForbidden
 	}
 	return resume, nil
 }
 
-// Create adds a new resume
-func (r *InMemoryResumeRepository) Create(resume Resume) (Resume, error) {
+// Create adds a new resume owned by the user
+func (r *InMemoryResumeRepository) Create(ownerID string, resume Resume) (Resume, error) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
@@ -66,114 +98,122 @@ func (r *InMemoryResumeRepository) Create(resume Resume) (Resume, error) {
 		return Resume{}, errors.New("resume with this ID already exists")
 	}
 
+	resume.OwnerID = ownerID
 	r.resumes[resume.ID] = resume
 	return resume, nil
 }
 
 // Update modifies an existing resume
-func (r *InMemoryResumeRepository) Update(id string, resume Resume) (Resume, error) {
+func (r *InMemoryResumeRepository) Update(ownerID, id string, resume Resume) (Resume, error) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	if _, exists := r.resumes[id]; !exists {
-		return Resume{}, errors.New("resume not found")
+	if _, err := r.findOwned(ownerID, id); err != nil {
+		return Resume{}, err
 	}
 
 	resume.ID = id
+	resume.OwnerID = ownerID
 	r.resumes[id] = resume
 	return resume, nil
 }
 
 // Delete removes a resume
-func (r *InMemoryResumeRepository) Delete(id string) error {
+func (r *InMemoryResumeRepository) Delete(ownerID, id string) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	if _, exists := r.resumes[id]; !exists {
-		return errors.New("resume not found")
+	if _, err := r.findOwned(ownerID, id); err != nil {
+		return err
 	}
 
 	delete(r.resumes, id)
 	return nil
 }
 
-// GetAllSkills returns all skills from all resumes
-func (r *InMemoryResumeRepository) GetAllSkills() []Skill {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
-
+// GetAllSkills returns all skills from the user's resumes
+func (r *InMemoryResumeRepository) GetAllSkills(ownerID string) []Skill {
 	var allSkills []Skill
-	for _, resume := range r.resumes {
+	for _, resume := range r.FindAll(ownerID) {
 		allSkills = append(allSkills, resume.Skills...)
 	}
 	return allSkills
 }
 
-// GetAllExperience returns all experiences from all resumes
-func (r *InMemoryResumeRepository) GetAllExperience() []Experience {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
-
+// GetAllExperience returns all experiences from the user's resumes
+func (r *InMemoryResumeRepository) GetAllExperience(ownerID string) []Experience {
 	var allExperience []Experience
-	for _, resume := range r.resumes {
+	for _, resume := range r.FindAll(ownerID) {
 		allExperience = append(allExperience, resume.Experience...)
 	}
 	return allExperience
 }
 
-// InitDemoData adds sample data to the repository
-func (r *InMemoryResumeRepository) InitDemoData() {
-	sampleResume := Resume{
-		ID: "sample",
-		BasicInfo: BasicInfo{
-			Name:     "John Doe",
-			Email:    "john.doe@example.com",
-			Phone:    "+1 (123) 456-7890",
-			Address:  "123 Main St, City, Country",
-			Website:  "https://johndoe.com",
-			LinkedIn: "https://linkedin.com/in/johndoe",
-			GitHub:   "https://github.com/johndoe",
-		},
-		Summary: "Software engineer with 5 years of experience in web development",
-		Experience: []Experience{
-			{
-				Company:     "Tech Company",
-				Position:    "Senior Developer",
-				StartDate:   "2021-01-01",
-				EndDate:     "Present",
-				Description: "Full-stack development with React and Go",
-				Highlights: []string{
-					"Improved application performance by 30%",
-					"Led a team of 5 developers",
-				},
-			},
-		},
-		Education: []Education{
-			{
-				Institution: "University of Example",
-				Degree:      "Bachelor's",
-				Field:       "Computer Science",
-				StartDate:   "2014-09-01",
-				EndDate:     "2018-06-30",
-				GPA:         "3.8",
-			},
-		},
-		Skills: []Skill{
-			{Name: "Go", Level: "Expert", Category: "Programming Languages"},
-			{Name: "React", Level: "Advanced", Category: "Frontend"},
-			{Name: "Docker", Level: "Intermediate", Category: "DevOps"},
-		},
-		Projects: []Project{
-			{
-				Name:        "Resume Builder",
-				Description: "A web application to create and manage resumes",
-				StartDate:   "2022-03-01",
-				EndDate:     "2022-06-01",
-				URL:         "https://github.com/johndoe/resume-builder",
-				Technologies: []string{"Go", "React", "PostgreSQL"},
-			},
-		},
+// FindTemplates returns the user's custom templates sorted by format and name
+func (r *InMemoryResumeRepository) FindTemplates(ownerID string) []CustomTemplate {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := []CustomTemplate{}
+	for _, template := range r.templates {
+		if template.OwnerID == ownerID {
+			result = append(result, template)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Format != result[j].Format {
+			return result[i].Format < result[j].Format
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// FindTemplate returns the user's custom template with the given format and name
+func (r *InMemoryResumeRepository) FindTemplate(ownerID, format, name string) (CustomTemplate, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, template := range r.templates {
+		if template.OwnerID == ownerID && template.Format == format && template.Name == name {
+			return template, nil
+		}
 	}
+	return CustomTemplate{}, ErrTemplateNotFound
+}
 
-	r.Create(sampleResume)
-} 
\ No newline at end of file
+// SaveTemplate creates a custom template or replaces the one with the same format and name
+func (r *InMemoryResumeRepository) SaveTemplate(ownerID string, template CustomTemplate) (CustomTemplate, bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	for id, existing := range r.templates {
+		if existing.OwnerID == ownerID && existing.Format == template.Format && existing.Name == template.Name {
+			existing.Content = template.Content
+			existing.UpdatedAt = now
+			r.templates[id] = existing
+			return existing, false, nil
+		}
+	}
+
+	template.ID = uuid.New().String()
+	template.OwnerID = ownerID
+	template.CreatedAt = now
+	template.UpdatedAt = now
+	r.templates[template.ID] = template
+	return template, true, nil
+}
+
+// DeleteTemplate removes one of the user's custom templates
+func (r *InMemoryResumeRepository) DeleteTemplate(ownerID, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	template, exists := r.templates[id]
+	if !exists || template.OwnerID != ownerID {
+		return ErrTemplateNotFound
+	}
+	delete(r.templates, id)
+	return nil
+}
@@ -0,0 +1,378 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// resumeSections maps the headings ParseResumeText recognises, in lower case,
+// to the section they start
+var resumeSections = map[string]string{
+	"summary":                     "summary",
+	"professional summary":        "summary",
+	"profile":                     "summary",
+	"professional profile":        "summary",
+	"about":                       "summary",
+	"about me":                    "summary",
+	"objective":                   "summary",
+	"career objective":            "summary",
+	"research interests":          "summary",
+	"experience":                  "experience",
+	"work experience":             "experience",
+	"professional experience":     "experience",
+	"employment":                  "experience",
+	"employment history":          "experience",
+	"work history":                "experience",
+	"appointments":                "experience",
+	"education":                   "education",
+	"academic background":         "education",
+	"skills":                      "skills",
+	"technical skills":            "skills",
+	"core competencies":           "skills",
+	"competencies":                "skills",
+	"certifications":              "certificates",
+	"certificates":                "certificates",
+	"licenses and certifications": "certificates",
+	"honours and certifications":  "certificates",
+	"honors and certifications":   "certificates",
+	"awards and certifications":   "certificates",
+	"projects":                    "projects",
+	"personal projects":           "projects",
+	"selected projects":           "projects",
+	"research and projects":       "projects",
+	"contact":                     "contact",
+	"contact information":         "contact",
+}
+
+const (
+	// textDate matches one date as resumes write it: a year, optionally with a
+	// month name before it or a month number around it
+	textDate = `(?:(?:jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?\s+)?` +
+		`(?:(?:19|20)\d{2}(?:[-/.](?:0?[1-9]|1[0-2]))?|(?:0?[1-9]|1[0-2])/(?:19|20)\d{2})`
+	// textOngoing matches the end of a date range that has not ended
+	textOngoing = `present|current|now|ongoing|today|sekarang`
+)
+
+var (
+	dateRangeSuffix  = regexp.MustCompile(`(?i)(?:^|[\s,|(])(` + textDate + `)\s*(?:-|–|—|to|until)\s*(` + textDate + `|` + textOngoing + `)\)?\s*$`)
+	singleDateSuffix = regexp.MustCompile(`(?i)(?:^|[\s,|(])(` + textDate + `)\)?\s*$`)
+	emailPattern     = regexp.MustCompile(`[\w.+-]+@[\w-]+(?:\.[\w-]+)+`)
+	urlPattern       = regexp.MustCompile(`(?i)^(?:https?://|www\.)\S+$|^[\w-]+(?:\.[\w-]+)+/\S*$`)
+	phonePattern     = regexp.MustCompile(`^\+?[\d\s().-]{7,}$`)
+	gpaPattern       = regexp.MustCompile(`(?i)\bGPA:?\s*([\d.,]+(?:\s*/\s*[\d.,]+)?)`)
+	skillSeparators  = regexp.MustCompile(`\s*(?:•|â€¢|·|;|\||,)\s*|\s+"\s+`)
+	headerSeparators = regexp.MustCompile(`\s+(?:\||·|•|â€¢)\s+`)
+)
+
+// bulletMarkers start the lines ParseResumeText treats as bullet points.
+// "â€¢" is how a UTF-8 bullet reads when a PDF encodes it as Windows-1252, and
+// `" ` how utils.PDFText reads the bullets of PDFs exported by this server.
+var bulletMarkers = []string{"•", "â€¢", "●", "▪", "◦", "‣", "- ", "– ", "* ", `" `}
+
+// ParseResumeText maps the plain text of a resume document to a Resume without
+// an LLM. It splits the text at common section headings, starts entries at
+// lines ending in a date range and turns bullet points into highlights, so it
+// works best on simple single-column layouts. Text it cannot place is dropped.
+func ParseResumeText(text string) Resume {
+	section := "header"
+	sections := map[string][]string{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			continue
+		}
+		if name, ok := resumeSections[headingKey(line)]; ok {
+			section = name
+			continue
+		}
+		sections[section] = append(sections[section], line)
+	}
+
+	var resume Resume
+	parseTextHeader(&resume, append(sections["header"], sections["contact"]...))
+	resume.Summary = strings.Join(sections["summary"], " ")
+	for _, entry := range splitTextEntries(sections["experience"]) {
+		resume.Experience = append(resume.Experience, entry.experience())
+	}
+	for _, entry := range splitTextEntries(sections["education"]) {
+		resume.Education = append(resume.Education, entry.education())
+	}
+	resume.Skills = parseTextSkills(sections["skills"])
+	for _, entry := range splitTextEntries(sections["certificates"]) {
+		resume.Certificates = append(resume.Certificates, entry.certificate())
+	}
+	for _, entry := range splitTextEntries(sections["projects"]) {
+		resume.Projects = append(resume.Projects, entry.project())
+	}
+
+	normalizeExtractedResume(&resume)
+	return resume
+}
+
+// headingKey returns the lookup key of a line in resumeSections
+func headingKey(line string) string {
+	key := strings.ToLower(strings.TrimRight(line, ": "))
+	return strings.ReplaceAll(key, "&", "and")
+}
+
+// parseTextHeader fills in the basic info from the lines above the first
+// heading. The first line that is not a contact detail is taken as the name.
+func parseTextHeader(resume *Resume, lines []string) {
+	info := &resume.BasicInfo
+	for _, line := range lines {
+		for _, part := range headerSeparators.Split(line, -1) {
+			label, value := "", part
+			if i := strings.Index(part, ": "); i > 0 && i < 20 {
+				label, value = strings.ToLower(part[:i]), strings.TrimSpace(part[i+2:])
+			}
+			lower := strings.ToLower(value)
+
+			switch {
+			case label == "email" || emailPattern.MatchString(value) && !strings.Contains(value, " "):
+				setIfEmpty(&info.Email, emailPattern.FindString(value))
+			case label == "linkedin" || strings.Contains(lower, "linkedin.com/"):
+				setIfEmpty(&info.LinkedIn, value)
+			case label == "github" || strings.Contains(lower, "github.com/"):
+				setIfEmpty(&info.GitHub, value)
+			case label == "website" || label == "portfolio" || urlPattern.MatchString(value):
+				setIfEmpty(&info.Website, value)
+			case label == "phone" || label == "mobile" || phonePattern.MatchString(value):
+				setIfEmpty(&info.Phone, value)
+			case label == "address" || label == "location":
+				setIfEmpty(&info.Address, value)
+			case info.Name == "" && label == "":
+				info.Name = value
+			case info.Address == "" && label == "" && strings.Contains(value, ","):
+				info.Address = value
+			}
+		}
+	}
+}
+
+// setIfEmpty keeps the first value found for a field
+func setIfEmpty(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
+
+// parseTextSkills reads skills from lines of separated names. A "Category:"
+// prefix sets the category of the skills on its line and "Name (Level)" the level.
+func parseTextSkills(lines []string) []Skill {
+	var skills []Skill
+	for _, line := range lines {
+		if text, ok := trimBullet(line); ok {
+			line = text
+		}
+		category := ""
+		if i := strings.Index(line, ":"); i > 0 && i < 30 {
+			category, line = strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		}
+		for _, name := range skillSeparators.Split(line, -1) {
+			skill := Skill{Name: name, Category: category}
+			if open := strings.LastIndex(name, " ("); open > 0 && strings.HasSuffix(name, ")") {
+				skill.Name, skill.Level = name[:open], name[open+2:len(name)-1]
+			}
+			skills = append(skills, skill)
+		}
+	}
+	return skills
+}
+
+// textEntry is one experience, education, certificate or project in a section
+type textEntry struct {
+	title      string
+	start, end string
+	lines      []string // Detail lines below the title
+	bullets    []string
+	// trailingDates is set when the dates came on their own line after the
+	// details, which means the entry is complete
+	trailingDates bool
+}
+
+// splitTextEntries groups the lines of a section into entries. An entry starts
+// at a line with a date range, at the first plain line after bullet points or
+// trailing dates, or at a line that looks like "Position | Company" below details.
+func splitTextEntries(lines []string) []*textEntry {
+	var entries []*textEntry
+	var current *textEntry
+	start := func(title string) {
+		current = &textEntry{title: title}
+		entries = append(entries, current)
+	}
+
+	for _, line := range lines {
+		if text, ok := trimBullet(line); ok {
+			if current == nil {
+				start("")
+			}
+			current.bullets = append(current.bullets, text)
+			continue
+		}
+
+		rest, from, to, dated := splitTextDates(line)
+		switch {
+		case dated && rest == "":
+			// Dates on their own line belong to the entry above, unless it has
+			// dates already: then the last detail line was the next title
+			if current == nil {
+				start("")
+			} else if current.start != "" && len(current.lines) > 0 {
+				title := current.lines[len(current.lines)-1]
+				current.lines = current.lines[:len(current.lines)-1]
+				start(title)
+			} else if current.start == "" {
+				current.trailingDates = len(current.lines) > 0
+			}
+			current.start, current.end = from, to
+		case dated && current != nil && current.start == "" && len(current.lines) == 0 && len(current.bullets) == 0 && !looksLikeTitle(rest):
+			// "Issuer, 2021" below a certificate name
+			current.lines = append(current.lines, rest)
+			current.start, current.end = from, to
+		case dated:
+			start(rest)
+			current.start, current.end = from, to
+		case current == nil, current.trailingDates && !gpaPattern.MatchString(line):
+			start(line)
+		case looksLikeTitle(line) && len(current.lines) > 0:
+			start(line)
+		case len(current.bullets) > 0 && startsLower(line):
+			// A bullet point wrapped onto the next line
+			current.bullets[len(current.bullets)-1] += " " + line
+		case len(current.bullets) > 0:
+			start(line)
+		default:
+			current.lines = append(current.lines, line)
+		}
+	}
+	return entries
+}
+
+// splitTextDates removes a trailing date range or date from a line
+func splitTextDates(line string) (rest, start, end string, ok bool) {
+	loc := dateRangeSuffix.FindStringSubmatchIndex(line)
+	if loc != nil {
+		start, end = line[loc[2]:loc[3]], line[loc[4]:loc[5]]
+	} else if loc = singleDateSuffix.FindStringSubmatchIndex(line); loc != nil {
+		start = line[loc[2]:loc[3]]
+	} else {
+		return line, "", "", false
+	}
+	if strings.EqualFold(end, "now") || strings.EqualFold(end, "current") || strings.EqualFold(end, "today") || strings.EqualFold(end, "ongoing") || strings.EqualFold(end, "sekarang") {
+		end = "Present"
+	}
+	return strings.TrimRight(line[:loc[0]], " ,|-–—("), start, end, true
+}
+
+// looksLikeTitle reports whether a line reads like "Position | Company" or "Position at Company"
+func looksLikeTitle(line string) bool {
+	return strings.Contains(line, " | ") || strings.Contains(line, " at ")
+}
+
+// trimBullet removes the bullet marker from a bullet point
+func trimBullet(line string) (string, bool) {
+	for _, marker := range bulletMarkers {
+		if strings.HasPrefix(line, marker) {
+			return strings.TrimSpace(strings.TrimPrefix(line, marker)), true
+		}
+	}
+	return line, false
+}
+
+// startsLower reports whether a line starts with a lower-case letter
+func startsLower(line string) bool {
+	return line != "" && strings.ToUpper(line[:1]) != line[:1]
+}
+
+// splitTitle splits "A | B", "A at B" or "A, B" into its two parts
+func splitTitle(title string) (string, string) {
+	for _, sep := range []string{" | ", " at ", ", ", " - "} {
+		if i := strings.Index(title, sep); i > 0 {
+			return strings.TrimSpace(title[:i]), strings.TrimSpace(title[i+len(sep):])
+		}
+	}
+	return title, ""
+}
+
+// experience reads the entry as "Position | Company" with a description
+func (e *textEntry) experience() Experience {
+	exp := Experience{StartDate: e.start, EndDate: e.end, Highlights: e.bullets}
+	exp.Position, exp.Company = splitTitle(e.title)
+	lines := e.lines
+	// A short line right below a bare position is usually the company
+	if exp.Company == "" && len(lines) > 0 && len(lines[0]) < 60 && !strings.HasSuffix(lines[0], ".") {
+		exp.Company, lines = lines[0], lines[1:]
+	}
+	exp.Description = strings.Join(lines, " ")
+	return exp
+}
+
+// education reads the entry as "Degree in Field" with the institution below it
+func (e *textEntry) education() Education {
+	edu := Education{StartDate: e.start, EndDate: e.end}
+	if i := strings.Index(e.title, " in "); i > 0 {
+		edu.Degree, edu.Field = e.title[:i], e.title[i+4:]
+	} else {
+		edu.Degree = e.title
+	}
+	for _, line := range append(append([]string(nil), e.lines...), e.bullets...) {
+		if m := gpaPattern.FindStringSubmatchIndex(line); m != nil {
+			edu.GPA = line[m[2]:m[3]]
+			line = strings.Trim(line[:m[0]]+line[m[1]:], " ,|")
+		}
+		if line != "" && edu.Institution == "" {
+			edu.Institution = line
+		}
+	}
+	// Some resumes put the institution first and the degree below it
+	if edu.Institution == "" {
+		edu.Institution, edu.Degree, edu.Field = edu.Degree, "", ""
+	}
+	return edu
+}
+
+// certificate reads the entry as a name with "Issuer | URL" below it
+func (e *textEntry) certificate() Certificate {
+	cert := Certificate{Name: e.title, IssueDate: e.start, ExpiryDate: e.end}
+	if cert.ExpiryDate == "Present" {
+		cert.ExpiryDate = ""
+	}
+	if cert.Name == "" && len(e.lines) > 0 {
+		cert.Name = e.lines[0]
+	}
+	for _, line := range e.lines {
+		for _, part := range strings.Split(line, " | ") {
+			if urlPattern.MatchString(part) {
+				setIfEmpty(&cert.URL, part)
+			} else if part != cert.Name {
+				setIfEmpty(&cert.Issuer, part)
+			}
+		}
+	}
+	// Compact layouts write "Name, Issuer, Date" on one line
+	if cert.Issuer == "" {
+		cert.Name, cert.Issuer = splitTitle(cert.Name)
+	}
+	return cert
+}
+
+// project reads the entry as a name with a description, a "Technologies:"
+// line and a URL below it
+func (e *textEntry) project() Project {
+	project := Project{Name: e.title, StartDate: e.start, EndDate: e.end}
+	var description []string
+	for _, line := range append(append([]string(nil), e.lines...), e.bullets...) {
+		switch {
+		case strings.HasPrefix(strings.ToLower(line), "technologies:"):
+			for _, tech := range strings.Split(line[len("technologies:"):], ",") {
+				project.Technologies = append(project.Technologies, strings.TrimSpace(tech))
+			}
+		case urlPattern.MatchString(line):
+			setIfEmpty(&project.URL, line)
+		default:
+			description = append(description, line)
+		}
+	}
+	project.Description = strings.Join(description, " ")
+	return project
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitTextDates(t *testing.T) {
+	tests := []struct {
+		line       string
+		rest       string
+		start, end string
+		ok         bool
+	}{
+		{"Software Engineer | Acme 2019 - 2021", "Software Engineer | Acme", "2019", "2021", true},
+		{"Engineer, Acme, Jan 2020 – Present", "Engineer, Acme", "Jan 2020", "Present", true},
+		{"Engineer (03/2018 to current)", "Engineer", "03/2018", "Present", true},
+		{"Oct. 2015 — 2017-06", "", "Oct. 2015", "2017-06", true},
+		{"AWS Solutions Architect, 2022", "AWS Solutions Architect", "2022", "", true},
+		{"Worked on 3000 tickets", "Worked on 3000 tickets", "", "", false},
+		{"Software Engineer", "Software Engineer", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			rest, start, end, ok := splitTextDates(tt.line)
+			if rest != tt.rest || start != tt.start || end != tt.end || ok != tt.ok {
+				t.Errorf("splitTextDates() = %q, %q, %q, %v, want %q, %q, %q, %v",
+					rest, start, end, ok, tt.rest, tt.start, tt.end, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseTextHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  BasicInfo
+	}{
+		{
+			name:  "separated contact details",
+			lines: []string{"Ada Lovelace", "ada@example.com | +44 20 7946 0958 | London, UK"},
+			want:  BasicInfo{Name: "Ada Lovelace", Email: "ada@example.com", Phone: "+44 20 7946 0958", Address: "London, UK"},
+		},
+		{
+			name:  "labelled contact details",
+			lines: []string{"Ada Lovelace", "Email: ada@example.com", "Location: London", "Portfolio: ada.dev"},
+			want:  BasicInfo{Name: "Ada Lovelace", Email: "ada@example.com", Address: "London", Website: "ada.dev"},
+		},
+		{
+			name:  "profile links",
+			lines: []string{"linkedin.com/in/ada · github.com/ada · https://ada.dev"},
+			want:  BasicInfo{LinkedIn: "linkedin.com/in/ada", GitHub: "github.com/ada", Website: "https://ada.dev"},
+		},
+		{
+			name:  "the first value found is kept",
+			lines: []string{"Ada Lovelace", "ada@example.com", "other@example.com"},
+			want:  BasicInfo{Name: "Ada Lovelace", Email: "ada@example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resume Resume
+			parseTextHeader(&resume, tt.lines)
+			if resume.BasicInfo != tt.want {
+				t.Errorf("parseTextHeader() = %+v, want %+v", resume.BasicInfo, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTextSkills(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []Skill
+	}{
+		{
+			name:  "separated names",
+			lines: []string{"Go, SQL; Docker • Kubernetes"},
+			want:  []Skill{{Name: "Go"}, {Name: "SQL"}, {Name: "Docker"}, {Name: "Kubernetes"}},
+		},
+		{
+			name:  "categories and levels",
+			lines: []string{"Languages: Go (Expert), Python", "- Tools: Git"},
+			want: []Skill{
+				{Name: "Go", Level: "Expert", Category: "Languages"},
+				{Name: "Python", Category: "Languages"},
+				{Name: "Git", Category: "Tools"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseTextSkills(tt.lines); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTextSkills() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResumeText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Resume
+	}{
+		{
+			name: "empty text",
+			text: "\n \n",
+			want: Resume{Experience: []Experience{}, Education: []Education{}, Skills: []Skill{}, Certificates: []Certificate{}, Projects: []Project{}},
+		},
+		{
+			name: "a simple single-column resume",
+			text: `Ada Lovelace
+ada@example.com | github.com/ada
+
+Summary
+Mathematician and writer,
+known for work on the Analytical Engine.
+
+Work Experience
+Analyst | Analytical Engines Ltd 2019 - 2021
+• Wrote the first published algorithm
+• Translated and annotated the memoir
+  of Luigi Menabrea
+Tutor
+Private practice
+Taught mathematics.
+2021 - now
+
+Education
+Mathematics in Analysis
+University of London, GPA: 3.9/4.0
+2015 - 2019
+
+Skills
+Languages: Go (Expert), Python
+
+Certifications
+Difference Engine Operator
+Royal Society, 2020
+
+Projects
+Note G 2022
+Bernoulli numbers on the Analytical Engine.
+Technologies: Punched cards, Ink
+https://example.com/note-g
+`,
+			want: Resume{
+				BasicInfo: BasicInfo{Name: "Ada Lovelace", Email: "ada@example.com", GitHub: "github.com/ada"},
+				Summary:   "Mathematician and writer, known for work on the Analytical Engine.",
+				Experience: []Experience{
+					{
+						Position: "Analyst", Company: "Analytical Engines Ltd", StartDate: "2019", EndDate: "2021",
+						Highlights: []string{"Wrote the first published algorithm", "Translated and annotated the memoir of Luigi Menabrea"},
+					},
+					{
+						Position: "Tutor", Company: "Private practice", StartDate: "2021", EndDate: "Present",
+						Description: "Taught mathematics.", Highlights: []string{},
+					},
+				},
+				Education: []Education{
+					{Degree: "Mathematics", Field: "Analysis", Institution: "University of London", GPA: "3.9/4.0", StartDate: "2015", EndDate: "2019"},
+				},
+				Skills: []Skill{
+					{Name: "Go", Level: "Expert", Category: "Languages"},
+					{Name: "Python", Category: "Languages"},
+				},
+				Certificates: []Certificate{
+					{Name: "Difference Engine Operator", Issuer: "Royal Society", IssueDate: "2020"},
+				},
+				Projects: []Project{
+					{
+						Name: "Note G", StartDate: "2022", Description: "Bernoulli numbers on the Analytical Engine.",
+						Technologies: []string{"Punched cards", "Ink"}, URL: "https://example.com/note-g",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseResumeText(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseResumeText() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// rrfK dampens the weight of the top ranks in reciprocal rank fusion. 60 is
+// the value from the original paper and works well without tuning.
+const rrfK = 60
+
+// candidateFactor is how many more chunks than requested each search
+// returns, so that fusion and reranking have something to choose from
+const candidateFactor = 4
+
+// RetrievalScores records how a retrieved chunk ranked in each stage of a
+// search. Ranks start at 1, and a rank of 0 means the stage did not find it.
+type RetrievalScores struct {
+	VectorRank   int      `json:"vector_rank,omitempty"`
+	Similarity   float64  `json:"similarity,omitempty"` // Cosine similarity to the query
+	KeywordRank  int      `json:"keyword_rank,omitempty"`
+	KeywordScore float64  `json:"keyword_score,omitempty"` // Full-text rank of the match
+	FusedScore   float64  `json:"fused_score"`             // Reciprocal rank fusion of both ranks
+	RerankScore  *float64 `json:"rerank_score,omitempty"`  // LLM relevance from 0 to 10, when reranked
+}
+
+// RetrievedDocument is a chunk found for a query, with its scores
+type RetrievedDocument struct {
+	VectorDocument
+	Scores RetrievalScores
+}
+
+// ChunkScore describes a retrieved chunk in the debug metadata of a chat response
+type ChunkScore struct {
+	ID         string `json:"id"`
+	DocumentID string `json:"document_id,omitempty"`
+	RetrievalScores
+}
+
+// chunkScores lists the scores of the retrieved chunks in rank order
+func chunkScores(docs []RetrievedDocument) []ChunkScore {
+	scores := make([]ChunkScore, 0, len(docs))
+	for _, doc := range docs {
+		documentID, _ := doc.Metadata["parent_id"].(string)
+		scores = append(scores, ChunkScore{ID: doc.ID, DocumentID: documentID, RetrievalScores: doc.Scores})
+	}
+	return scores
+}
+
+// fuseRankings merges vector and keyword results with reciprocal rank
+// fusion: every chunk scores 1/(rrfK+rank) for each list it appears in.
+// Ties keep the vector order first.
+func fuseRankings(vector, keyword []RetrievedDocument) []RetrievedDocument {
+	var fused []RetrievedDocument
+	position := map[string]int{}
+	add := func(doc RetrievedDocument) *RetrievedDocument {
+		if i, ok := position[doc.ID]; ok {
+			return &fused[i]
+		}
+		position[doc.ID] = len(fused)
+		fused = append(fused, doc)
+		return &fused[len(fused)-1]
+	}
+
+	for i, doc := range vector {
+		entry := add(doc)
+		entry.Scores.VectorRank = i + 1
+		entry.Scores.FusedScore += 1 / float64(rrfK+i+1)
+	}
+	for i, doc := range keyword {
+		entry := add(doc)
+		entry.Scores.KeywordRank = i + 1
+		entry.Scores.KeywordScore = doc.Scores.KeywordScore
+		entry.Scores.FusedScore += 1 / float64(rrfK+i+1)
+	}
+
+	sort.SliceStable(fused, func(i, j int) bool {
+		return fused[i].Scores.FusedScore > fused[j].Scores.FusedScore
+	})
+	return fused
+}
+
+// rerankPassageSize limits how much of each chunk is shown to the reranker
+const rerankPassageSize = 1500
+
+// rerankPrompt is the system prompt for reranking
+const rerankPrompt = `You judge how useful passages are for answering a question.
+
+Rate every passage from 0 (unrelated) to 10 (answers the question directly). Reply with a single JSON
+object mapping each passage number to its rating and nothing else, for example {"1": 8, "2": 0, "3": 5}.`
+
+// Reranker reorders retrieved chunks by how relevant an LLM judges them to
+// the query. It catches chunks that match the words of a query but not its
+// meaning, which neither search can tell apart.
+type Reranker struct {
+	llm LLMClient
+}
+
+// NewReranker creates a reranker that asks llm for relevance ratings
+func NewReranker(llm LLMClient) *Reranker {
+	return &Reranker{llm: llm}
+}
+
+// Rerank sorts docs by the LLM's rating, keeping the fused order between
+// chunks with the same rating. Chunks the LLM did not rate count as 0.
+func (r *Reranker) Rerank(ctx context.Context, query string, docs []RetrievedDocument) ([]RetrievedDocument, error) {
+	if len(docs) == 0 {
+		return docs, nil
+	}
+
+	var passages strings.Builder
+	for i, doc := range docs {
+		content := doc.Content
+		if runes := []rune(content); len(runes) > rerankPassageSize {
+			content = string(runes[:rerankPassageSize]) + "..."
+		}
+		fmt.Fprintf(&passages, "[%d] %s\n\n", i+1, content)
+	}
+
+	reply, err := r.llm.Complete(ctx, []Message{
+		{Role: "system", Content: rerankPrompt},
+		{Role: "user", Content: "Question: " + query + "\n\nPassages:\n\n" + passages.String()},
+	}, CompletionOptions{Temperature: Float64(0.1)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ratings from LLM: %w", err)
+	}
+
+	ratings, err := parseRatings(reply)
+	if err != nil {
+		return nil, err
+	}
+
+	reranked := make([]RetrievedDocument, len(docs))
+	copy(reranked, docs)
+	for i := range reranked {
+		rating := ratings[strconv.Itoa(i+1)]
+		reranked[i].Scores.RerankScore = &rating
+	}
+	sort.SliceStable(reranked, func(i, j int) bool {
+		return *reranked[i].Scores.RerankScore > *reranked[j].Scores.RerankScore
+	})
+	return reranked, nil
+}
+
+// parseRatings decodes the JSON object of passage ratings in a reply
+func parseRatings(reply string) (map[string]float64, error) {
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return nil, errors.New("reply does not contain a JSON object")
+	}
+
+	var ratings map[string]float64
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &ratings); err != nil {
+		return nil, fmt.Errorf("invalid ratings JSON: %w", err)
+	}
+	return ratings, nil
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// retrieved returns chunks with the given IDs, in order
+func retrieved(ids ...string) []RetrievedDocument {
+	docs := make([]RetrievedDocument, 0, len(ids))
+	for _, id := range ids {
+		docs = append(docs, RetrievedDocument{VectorDocument: VectorDocument{ID: id, Content: "content of " + id}})
+	}
+	return docs
+}
+
+// ids lists the IDs of chunks in order
+func ids(docs []RetrievedDocument) []string {
+	result := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		result = append(result, doc.ID)
+	}
+	return result
+}
+
+func TestFuseRankings(t *testing.T) {
+	rrf := func(ranks ...int) float64 {
+		score := 0.0
+		for _, rank := range ranks {
+			score += 1 / float64(rrfK+rank)
+		}
+		return score
+	}
+
+	tests := []struct {
+		name    string
+		vector  []RetrievedDocument
+		keyword []RetrievedDocument
+		want    []string
+		scores  map[string]RetrievalScores
+	}{
+		{
+			name: "no results",
+			want: []string{},
+		},
+		{
+			name:   "vector results only keep their order",
+			vector: retrieved("a", "b"),
+			want:   []string{"a", "b"},
+			scores: map[string]RetrievalScores{
+				"a": {VectorRank: 1, FusedScore: rrf(1)},
+				"b": {VectorRank: 2, FusedScore: rrf(2)},
+			},
+		},
+		{
+			name:    "chunks found by both searches rank first",
+			vector:  retrieved("a", "b", "c"),
+			keyword: retrieved("c", "d"),
+			want:    []string{"c", "a", "b", "d"},
+			scores: map[string]RetrievalScores{
+				"a": {VectorRank: 1, FusedScore: rrf(1)},
+				"b": {VectorRank: 2, FusedScore: rrf(2)},
+				"c": {VectorRank: 3, KeywordRank: 1, FusedScore: rrf(3, 1)},
+				"d": {KeywordRank: 2, FusedScore: rrf(2)},
+			},
+		},
+		{
+			name:    "ties keep the vector order first",
+			vector:  retrieved("a"),
+			keyword: retrieved("b"),
+			want:    []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fused := fuseRankings(tt.vector, tt.keyword)
+			if got := ids(fused); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("fuseRankings() = %v, want %v", got, tt.want)
+			}
+			for _, doc := range fused {
+				want, ok := tt.scores[doc.ID]
+				if !ok {
+					continue
+				}
+				got := doc.Scores
+				if got.VectorRank != want.VectorRank || got.KeywordRank != want.KeywordRank || math.Abs(got.FusedScore-want.FusedScore) > 1e-12 {
+					t.Errorf("scores of %s = %+v, want %+v", doc.ID, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRerank(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		want    []string
+		ratings []float64 // In the order of want
+		wantErr bool
+	}{
+		{
+			name:    "sorts by rating",
+			reply:   `{"1": 2, "2": 9, "3": 5}`,
+			want:    []string{"b", "c", "a"},
+			ratings: []float64{9, 5, 2},
+		},
+		{
+			name:    "ties and unrated chunks keep the fused order",
+			reply:   `Here you go: {"1": 4, "3": 4}`,
+			want:    []string{"a", "c", "b"},
+			ratings: []float64{4, 4, 0},
+		},
+		{
+			name:    "a reply without JSON is an error",
+			reply:   "All of them are relevant.",
+			wantErr: true,
+		},
+		{
+			name:    "malformed JSON is an error",
+			reply:   `{"1": "high"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := NewFakeLLMClient(tt.reply)
+			reranked, err := NewReranker(llm).Rerank(context.Background(), "query", retrieved("a", "b", "c"))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Rerank() = %v, want an error", ids(reranked))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Rerank() error = %v", err)
+			}
+			if got := ids(reranked); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Rerank() = %v, want %v", got, tt.want)
+			}
+			for i, doc := range reranked {
+				if doc.Scores.RerankScore == nil || *doc.Scores.RerankScore != tt.ratings[i] {
+					t.Errorf("rating of %s = %v, want %v", doc.ID, doc.Scores.RerankScore, tt.ratings[i])
+				}
+			}
+
+			calls := llm.Calls()
+			if len(calls) != 1 || calls[0].Options.Temperature == nil || *calls[0].Options.Temperature != 0.1 {
+				t.Errorf("Rerank() made calls %+v, want one at temperature 0.1", calls)
+			}
+		})
+	}
+}
+
+func TestRerankLLMError(t *testing.T) {
+	llm := NewFakeLLMClient()
+	llm.Script(ScriptedResponse{Err: errors.New("unavailable")})
+	if _, err := NewReranker(llm).Rerank(context.Background(), "query", retrieved("a")); err == nil {
+		t.Fatal("Rerank() error = nil, want the LLM error")
+	}
+
+	// Nothing to rerank needs no LLM call
+	if _, err := NewReranker(llm).Rerank(context.Background(), "query", nil); err != nil {
+		t.Fatalf("Rerank() of no chunks error = %v", err)
+	}
+}
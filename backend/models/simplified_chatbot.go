@@ -1,64 +1,63 @@
 package models
 
 import (
-	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/pgvector/pgvector-go"
 	"resume.in/backend/utils"
-	"resume.in/backend/config"
 )
 
 // SimplePostgresChatbotRepository provides a simplified implementation
 // without LangChain dependencies to avoid build issues
 type SimplePostgresChatbotRepository struct {
-	db *sqlx.DB
-}
+	db       *sqlx.DB
+	embedder Embedder
+	llm      LLMClient
+	chunker  Chunker
+	reranker *Reranker // Optional
 
-// OpenRouterRequest represents a request to the Open Router API
-type OpenRouterRequest struct {
-	Model       string    `json:"model"`
-	Messages    []Message `json:"messages"`
-	MaxTokens   int       `json:"max_tokens"`
-	Temperature float64   `json:"temperature,omitempty"`
-}
+	// summarizer condenses older messages of long sessions, and summarizing
+	// holds the IDs of the sessions it is working on
+	summarizer  *Summarizer // Optional
+	summarizing sync.Map
 
-// Message represents a chat message in the Open Router API format
-type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
+	// contextBuilder fits prompts into the LLM's context window
+	contextBuilder ContextBuilder
 
-// OpenRouterResponse represents a response from the Open Router API
-type OpenRouterResponse struct {
-	Choices []struct {
-		Message struct {
-			Role    string `json:"role"`
-			Content string `json:"content"`
-		} `json:"message"`
-	} `json:"choices"`
-	Error *struct {
-		Message string `json:"message"`
-	} `json:"error,omitempty"`
+	// iterativeScan is set when pgvector can keep scanning the ivfflat index
+	// until a filtered search has found enough rows (pgvector 0.8 and later)
+	iterativeScan bool
 }
 
 // NewPostgresChatbotRepository creates a new PostgreSQL chatbot repository
 // This is the function called from main.go
-func NewPostgresChatbotRepository(db *sqlx.DB) (ChatbotRepository, error) {
-	return NewSimplePostgresChatbotRepository(db)
+func NewPostgresChatbotRepository(db *sqlx.DB, embedder Embedder, llm LLMClient, chunker Chunker, reranker *Reranker, summarizer *Summarizer, contextBuilder ContextBuilder) (ChatbotRepository, error) {
+	return NewSimplePostgresChatbotRepository(db, embedder, llm, chunker, reranker, summarizer, contextBuilder)
 }
 
-// NewSimplePostgresChatbotRepository creates a new PostgreSQL chatbot repository
-func NewSimplePostgresChatbotRepository(db *sqlx.DB) (*SimplePostgresChatbotRepository, error) {
+// NewSimplePostgresChatbotRepository creates a new PostgreSQL chatbot repository.
+// A nil reranker leaves retrieved documents in their fused order, and a nil
+// summarizer leaves session summaries as they are.
+func NewSimplePostgresChatbotRepository(db *sqlx.DB, embedder Embedder, llm LLMClient, chunker Chunker, reranker *Reranker, summarizer *Summarizer, contextBuilder ContextBuilder) (*SimplePostgresChatbotRepository, error) {
 	repo := &SimplePostgresChatbotRepository{
-		db: db,
+		db:       db,
+		embedder: embedder,
+		llm:      llm,
+		chunker:  chunker,
+		reranker: reranker,
+
+		summarizer:     summarizer,
+		contextBuilder: contextBuilder,
 	}
 	
 	// Initialize tables
@@ -66,6 +65,7 @@ func NewSimplePostgresChatbotRepository(db *sqlx.DB) (*SimplePostgresChatbotRepo
 		return nil, err
 	}
 	
+	utils.Info("Using embedder %s with %d dimensions", embedder.Name(), embedder.Dimensions())
 	return repo, nil
 }
 
@@ -77,34 +77,62 @@ func (r *SimplePostgresChatbotRepository) initTables() error {
 		return err
 	}
 	
-	// Create chat_messages table
-	_, err = r.db.Exec(`
+	// Create chat_messages table (chat_sessions is created by the migrations)
+	_, err = r.db.Exec(fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS chat_messages (
 			id SERIAL PRIMARY KEY,
 			session_id VARCHAR(255) NOT NULL,
 			role VARCHAR(50) NOT NULL,
 			content TEXT NOT NULL,
-			embedding vector(1536),
+			embedding vector(%d),
 			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 		)
-	`)
+	`, r.embedder.Dimensions()))
 	if err != nil {
 		return err
 	}
 	
 	// Create vector_documents table
-	_, err = r.db.Exec(`
+	_, err = r.db.Exec(fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS vector_documents (
 			id VARCHAR(255) PRIMARY KEY,
 			content TEXT NOT NULL,
 			metadata JSONB,
-			embedding vector(1536) NOT NULL
+			embedding vector(%d) NOT NULL
 		)
+	`, r.embedder.Dimensions()))
+	if err != nil {
+		return err
+	}
+	
+	// Documents stored before chunking are their own single chunk
+	_, err = r.db.Exec(`
+		UPDATE vector_documents
+		SET metadata = COALESCE(metadata, '{}'::jsonb) || jsonb_build_object('parent_id', id, 'chunk_index', 0, 'chunk_count', 1, 'offset', 0)
+		WHERE metadata->>'parent_id' IS NULL;
+		
+		CREATE INDEX IF NOT EXISTS vector_documents_parent_idx ON vector_documents ((metadata->>'parent_id'));
+		CREATE INDEX IF NOT EXISTS vector_documents_uploaded_by_idx ON vector_documents ((metadata->>'uploaded_by'));
+		CREATE INDEX IF NOT EXISTS vector_documents_session_idx ON vector_documents ((metadata->>'session_id'));
+		CREATE INDEX IF NOT EXISTS vector_documents_tags_idx ON vector_documents USING GIN ((metadata->'tags'));
+		
+		ALTER TABLE vector_documents
+		ADD COLUMN IF NOT EXISTS created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+		
+		ALTER TABLE vector_documents
+		ADD COLUMN IF NOT EXISTS content_tsv tsvector GENERATED ALWAYS AS (to_tsvector('english', content)) STORED;
+		
+		CREATE INDEX IF NOT EXISTS vector_documents_content_tsv_idx ON vector_documents USING GIN (content_tsv);
 	`)
 	if err != nil {
 		return err
 	}
 	
+	// Bring stored vectors in line with the configured embedder
+	if err := r.syncEmbeddings(context.Background()); err != nil {
+		return err
+	}
+	
 	// Create index on the embedding column
 	_, err = r.db.Exec(`
 		CREATE INDEX IF NOT EXISTS vector_documents_embedding_idx 
@@ -112,49 +140,355 @@ func (r *SimplePostgresChatbotRepository) initTables() error {
 		USING ivfflat (embedding vector_cosine_ops)
 		WITH (lists = 100)
 	`)
+	if err != nil {
+		return err
+	}
 	
+	var version string
+	if err := r.db.Get(&version, `SELECT extversion FROM pg_extension WHERE extname = 'vector'`); err != nil {
+		return err
+	}
+	var major, minor int
+	fmt.Sscanf(version, "%d.%d", &major, &minor)
+	r.iterativeScan = major > 0 || minor >= 8
+	
+	return nil
+}
+
+// syncEmbeddings makes stored vectors match the configured embedder. Vectors
+// from a different model (or the placeholder vectors written before embedders
+// existed) are meaningless to similarity search, so when the embedder changes
+// document vectors are recomputed and message vectors are cleared.
+func (r *SimplePostgresChatbotRepository) syncEmbeddings(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS embedding_state (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			embedder TEXT NOT NULL,
+			dimensions INTEGER NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	name, dims := r.embedder.Name(), r.embedder.Dimensions()
+
+	var storedName string
+	var storedDims int
+	err = r.db.QueryRowContext(ctx, `SELECT embedder, dimensions FROM embedding_state WHERE id = 1`).Scan(&storedName, &storedDims)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil && storedName == name && storedDims == dims {
+		return nil
+	}
+
+	utils.Info("Embedder changed from %q (%d dimensions) to %q (%d dimensions), re-embedding stored documents",
+		storedName, storedDims, name, dims)
+
+	// pgvector stores the dimension count as the column's type modifier
+	var columnDims int
+	err = r.db.QueryRowContext(ctx, `
+		SELECT atttypmod FROM pg_attribute
+		WHERE attrelid = 'vector_documents'::regclass AND attname = 'embedding'
+	`).Scan(&columnDims)
+	if err != nil {
+		return err
+	}
+
+	if columnDims != dims {
+		// Vectors cannot be cast between sizes, so clear them and recompute below
+		_, err = r.db.ExecContext(ctx, fmt.Sprintf(`
+			DROP INDEX IF EXISTS vector_documents_embedding_idx;
+			ALTER TABLE vector_documents ALTER COLUMN embedding DROP NOT NULL;
+			ALTER TABLE vector_documents ALTER COLUMN embedding TYPE vector(%[1]d) USING NULL;
+			ALTER TABLE chat_messages ALTER COLUMN embedding TYPE vector(%[1]d) USING NULL;
+		`, dims))
+		if err != nil {
+			return fmt.Errorf("failed to resize embedding columns: %w", err)
+		}
+	} else if _, err := r.db.ExecContext(ctx, `UPDATE chat_messages SET embedding = NULL`); err != nil {
+		return err
+	}
+
+	if err := r.reembedDocuments(ctx); err != nil {
+		// Keep serving; the old state is left in place so the next start retries
+		utils.Error("Failed to re-embed documents, retrieval will be degraded until the next restart: %v", err)
+		return nil
+	}
+
+	if _, err := r.db.ExecContext(ctx, `ALTER TABLE vector_documents ALTER COLUMN embedding SET NOT NULL`); err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO embedding_state (id, embedder, dimensions, updated_at)
+		VALUES (1, $1, $2, NOW())
+		ON CONFLICT (id) DO UPDATE SET embedder = $1, dimensions = $2, updated_at = NOW()
+	`, name, dims)
 	return err
 }
 
-// SaveMessage saves a chat message to the database
-func (r *SimplePostgresChatbotRepository) SaveMessage(ctx context.Context, message ChatMessage) (ChatMessage, error) {
+// reembedDocuments recomputes the embedding of every stored document
+func (r *SimplePostgresChatbotRepository) reembedDocuments(ctx context.Context) error {
+	type row struct {
+		ID      string `db:"id"`
+		Content string `db:"content"`
+	}
+
+	lastID := ""
+	total := 0
+	for {
+		var rows []row
+		err := r.db.SelectContext(ctx, &rows, `
+			SELECT id, content FROM vector_documents
+			WHERE id > $1
+			ORDER BY id
+			LIMIT $2
+		`, lastID, embedBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		texts := make([]string, len(rows))
+		for i, row := range rows {
+			texts[i] = row.Content
+		}
+
+		vectors, err := r.embedder.Embed(ctx, texts)
+		if err != nil {
+			return err
+		}
+
+		for i, row := range rows {
+			_, err := r.db.ExecContext(ctx, `UPDATE vector_documents SET embedding = $1 WHERE id = $2`,
+				pgvector.NewVector(vectors[i]), row.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		total += len(rows)
+		lastID = rows[len(rows)-1].ID
+	}
+
+	utils.Info("Re-embedded %d documents", total)
+	return nil
+}
+
+// EnsureSession returns the session with the given ID, creating it for the
+// user if it does not exist yet. It returns ErrSessionForbidden if the session
+// belongs to another user.
+func (r *SimplePostgresChatbotRepository) EnsureSession(ctx context.Context, sessionID, userID, title string) (ChatSession, error) {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO chat_sessions (id, user_id, title, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (id) DO NOTHING
+	`, sessionID, userID, title)
+	if err != nil {
+		return ChatSession{}, err
+	}
+
+	return r.GetSession(ctx, userID, sessionID)
+}
+
+// GetSession retrieves a session owned by the user
+func (r *SimplePostgresChatbotRepository) GetSession(ctx context.Context, userID, sessionID string) (ChatSession, error) {
 	query := `
-		INSERT INTO chat_messages (session_id, role, content, embedding, created_at)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id
+		SELECT id, user_id, title, resume_id, summary, created_at, updated_at
+		FROM chat_sessions
+		WHERE id = $1
 	`
-	var id int64
-	var embedVector pgvector.Vector
 
-	if message.Embedding != nil {
-		embedVector = pgvector.NewVector(message.Embedding)
+	var session ChatSession
+	var owner, resumeID sql.NullString
+	err := r.db.QueryRowContext(ctx, query, sessionID).Scan(
+		&session.ID,
+		&owner,
+		&session.Title,
+		&resumeID,
+		&session.Summary,
+		&session.CreatedAt,
+		&session.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ChatSession{}, ErrSessionNotFound
+		}
+		return ChatSession{}, err
 	}
 
-	err := r.db.QueryRowContext(
-		ctx,
-		query,
-		message.SessionID,
-		message.Role,
-		message.Content,
-		embedVector,
-		time.Now(),
-	).Scan(&id)
+	if owner.String != userID {
+		return ChatSession{}, ErrSessionForbidden
+	}
+
+	session.UserID = owner.String
+	session.ResumeID = resumeID.String
+	return session, nil
+}
+
+// ListSessions returns the user's sessions, most recently active first
+func (r *SimplePostgresChatbotRepository) ListSessions(ctx context.Context, userID string) ([]ChatSession, error) {
+	query := `
+		SELECT id, user_id, title, COALESCE(resume_id, ''), summary, created_at, updated_at
+		FROM chat_sessions
+		WHERE user_id = $1
+		ORDER BY updated_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []ChatSession{}
+	for rows.Next() {
+		var session ChatSession
+		if err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.Title,
+			&session.ResumeID,
+			&session.Summary,
+			&session.CreatedAt,
+			&session.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// LinkSessionResume records the resume saved from a session owned by the
+// user. Only a session without a resume is linked, so that of two concurrent
+// saves only one wins.
+func (r *SimplePostgresChatbotRepository) LinkSessionResume(ctx context.Context, userID, sessionID, resumeID string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE chat_sessions
+		SET resume_id = $3
+		WHERE id = $1 AND user_id = $2 AND resume_id IS NULL
+	`, sessionID, userID, resumeID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		// Tell a missing session apart from someone else's, and from one
+		// that already has a resume
+		if _, err := r.GetSession(ctx, userID, sessionID); err != nil {
+			return err
+		}
+		return ErrSessionResumeLinked
+	}
+
+	return nil
+}
+
+// SaveMessage saves a chat message to the database, embedding it if it has
+// no embedding yet
+func (r *SimplePostgresChatbotRepository) SaveMessage(ctx context.Context, message ChatMessage) (ChatMessage, error) {
+	r.embedMessage(ctx, &message)
 
+	saved, err := insertMessage(ctx, r.db, message)
 	if err != nil {
 		return ChatMessage{}, err
 	}
 
-	message.ID = id
+	// Keep the session's activity time current for session listings
+	if _, err := r.db.ExecContext(ctx, `UPDATE chat_sessions SET updated_at = NOW() WHERE id = $1`, message.SessionID); err != nil {
+		utils.Warning("Failed to update session timestamp: %v", err)
+	}
+
+	return saved, nil
+}
+
+// SaveTurn saves a user message and the assistant's answer to it in one
+// transaction, so the history never holds a question without its answer.
+// Messages without an embedding are embedded first, each on its own.
+func (r *SimplePostgresChatbotRepository) SaveTurn(ctx context.Context, question, answer ChatMessage) (ChatMessage, ChatMessage, error) {
+	// Embed before opening the transaction, since it can take a while
+	r.embedMessage(ctx, &question)
+	r.embedMessage(ctx, &answer)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return ChatMessage{}, ChatMessage{}, err
+	}
+	defer tx.Rollback()
+
+	if question, err = insertMessage(ctx, tx, question); err != nil {
+		return ChatMessage{}, ChatMessage{}, fmt.Errorf("failed to save user message: %w", err)
+	}
+	if answer, err = insertMessage(ctx, tx, answer); err != nil {
+		return ChatMessage{}, ChatMessage{}, fmt.Errorf("failed to save assistant message: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE chat_sessions SET updated_at = NOW() WHERE id = $1`, question.SessionID); err != nil {
+		return ChatMessage{}, ChatMessage{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ChatMessage{}, ChatMessage{}, err
+	}
+	return question, answer, nil
+}
+
+// embedMessage embeds a message's content unless it already has an
+// embedding. A message that cannot be embedded is saved without one.
+func (r *SimplePostgresChatbotRepository) embedMessage(ctx context.Context, message *ChatMessage) {
+	if message.Embedding != nil {
+		return
+	}
+	embedding, err := embedOne(ctx, r.embedder, message.Content)
+	if err != nil {
+		utils.Warning("Failed to embed chat message, saving it without an embedding: %v", err)
+		return
+	}
+	message.Embedding = embedding
+}
+
+// insertMessage writes a message to chat_messages and returns it with its ID.
+// Messages without a creation time are stamped with the current time.
+func insertMessage(ctx context.Context, db sqlx.QueryerContext, message ChatMessage) (ChatMessage, error) {
+	var embedVector *pgvector.Vector
+	if message.Embedding != nil {
+		vector := pgvector.NewVector(message.Embedding)
+		embedVector = &vector
+	}
+	if message.CreatedAt.IsZero() {
+		message.CreatedAt = time.Now()
+	}
+
+	err := db.QueryRowxContext(ctx, `
+		INSERT INTO chat_messages (session_id, role, content, embedding, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, message.SessionID, message.Role, message.Content, embedVector, message.CreatedAt).Scan(&message.ID)
+	if err != nil {
+		return ChatMessage{}, err
+	}
 	return message, nil
 }
 
-// GetSessionMessages retrieves all messages for a given session
+// GetSessionMessages retrieves all messages for a given session in the order
+// they were saved. The messages of a turn share a timestamp, so they are
+// ordered by ID, which the session summary also uses to mark its cut-off.
 func (r *SimplePostgresChatbotRepository) GetSessionMessages(ctx context.Context, sessionID string) ([]ChatMessage, error) {
 	query := `
 		SELECT id, session_id, role, content, created_at
 		FROM chat_messages
 		WHERE session_id = $1
-		ORDER BY created_at ASC
+		ORDER BY id ASC
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, sessionID)
@@ -181,7 +515,8 @@ func (r *SimplePostgresChatbotRepository) GetSessionMessages(ctx context.Context
 	return messages, nil
 }
 
-// StoreDocument stores a document in the vector database
+// StoreDocument stores a document in the vector database as a single chunk,
+// unless its metadata names the parent document it is a chunk of
 func (r *SimplePostgresChatbotRepository) StoreDocument(ctx context.Context, doc VectorDocument) error {
 	query := `
 		INSERT INTO vector_documents (id, content, metadata, embedding)
@@ -193,18 +528,19 @@ func (r *SimplePostgresChatbotRepository) StoreDocument(ctx context.Context, doc
 	if doc.ID == "" {
 		doc.ID = uuid.New().String()
 	}
+	if _, ok := doc.Metadata["parent_id"]; !ok {
+		doc.Metadata = chunkMetadata(doc.Metadata, doc.ID, Chunk{}, 1)
+	}
 
 	metadata, err := json.Marshal(doc.Metadata)
 	if err != nil {
 		return err
 	}
 
-	// Use a simple float32 array with values between 0-1 for demo purposes
-	// In a real implementation, this would be generated by an embedding model
 	if doc.Embedding == nil {
-		doc.Embedding = make([]float32, 1536)
-		for i := range doc.Embedding {
-			doc.Embedding[i] = float32(i % 100) / 100.0
+		doc.Embedding, err = embedOne(ctx, r.embedder, doc.Content)
+		if err != nil {
+			return fmt.Errorf("failed to embed document: %w", err)
 		}
 	}
 
@@ -221,32 +557,181 @@ func (r *SimplePostgresChatbotRepository) StoreDocument(ctx context.Context, doc
 	return err
 }
 
-// SearchSimilarDocuments searches for similar documents in the vector database
-func (r *SimplePostgresChatbotRepository) SearchSimilarDocuments(ctx context.Context, embedding []float32, limit int) ([]VectorDocument, error) {
-	query := `
-		SELECT id, content, metadata
-		FROM vector_documents
-		ORDER BY embedding <=> $1
-		LIMIT $2
-	`
+// searchProbes is the number of ivfflat lists a search scans when pgvector
+// cannot scan iteratively. The index holds every user's documents and is
+// filtered afterwards, so scanning only the default single list would often
+// find none of the user's.
+const searchProbes = 10
+
+// SearchSimilarDocuments searches the documents in scope for the ones most
+// similar to the embedding. The filters sit in the WHERE clause of the ANN
+// query, so Postgres can still use the ivfflat index and filter its rows, or
+// use the uploaded_by index when the user has few documents.
+func (r *SimplePostgresChatbotRepository) SearchSimilarDocuments(ctx context.Context, embedding []float32, scope SearchScope, limit int) ([]VectorDocument, error) {
+	retrieved, err := r.vectorSearch(ctx, embedding, scope, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := make([]VectorDocument, 0, len(retrieved))
+	for _, doc := range retrieved {
+		documents = append(documents, doc.VectorDocument)
+	}
+	return documents, nil
+}
+
+// RetrieveDocuments finds the chunks in scope that best match a query. A
+// vector search and a full-text search each return candidates, which are
+// merged with reciprocal rank fusion so that exact matches on names and codes
+// rank well even when their embeddings are not close to the query's. With a
+// reranker, the best fused candidates are reordered by the LLM. Without an
+// embedding only the full-text search runs.
+func (r *SimplePostgresChatbotRepository) RetrieveDocuments(ctx context.Context, query string, embedding []float32, scope SearchScope, limit int) ([]RetrievedDocument, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+	candidates := limit * candidateFactor
+
+	var vector []RetrievedDocument
+	if embedding != nil {
+		var err error
+		if vector, err = r.vectorSearch(ctx, embedding, scope, candidates); err != nil {
+			return nil, fmt.Errorf("vector search failed: %w", err)
+		}
+	}
+	keyword, err := r.keywordSearch(ctx, query, scope, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("keyword search failed: %w", err)
+	}
+
+	docs := fuseRankings(vector, keyword)
+	if r.reranker != nil && len(docs) > 1 {
+		// Rating every candidate would make the prompt long for little gain
+		docs = docs[:min(len(docs), 2*limit)]
+		reranked, err := r.reranker.Rerank(ctx, query, docs)
+		if err != nil {
+			utils.Warning("Reranking failed, keeping the fused order: %v", err)
+		} else {
+			docs = reranked
+		}
+	}
+
+	return docs[:min(len(docs), limit)], nil
+}
 
+// vectorSearch runs the ANN query for SearchSimilarDocuments
+func (r *SimplePostgresChatbotRepository) vectorSearch(ctx context.Context, embedding []float32, scope SearchScope, limit int) ([]RetrievedDocument, error) {
 	if limit <= 0 {
 		limit = 5
 	}
 
-	embedVector := pgvector.NewVector(embedding)
-	rows, err := r.db.QueryContext(ctx, query, embedVector, limit)
+	conditions, args, err := scopeConditions(scope, []interface{}{pgvector.NewVector(embedding)})
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, limit)
+
+	// An iterative scan returns rows only roughly in order of distance, so
+	// the outer query sorts them again
+	query := fmt.Sprintf(`
+		WITH nearest AS MATERIALIZED (
+			SELECT id, content, metadata, embedding <=> $1 AS distance
+			FROM vector_documents
+			WHERE %s
+			ORDER BY distance
+			LIMIT $%d
+		)
+		SELECT id, content, metadata, 1 - distance
+		FROM nearest
+		ORDER BY distance
+	`, conditions, len(args))
+
+	// The scan settings only apply to this transaction
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	setting := fmt.Sprintf(`SET LOCAL ivfflat.probes = %d`, searchProbes)
+	if r.iterativeScan {
+		// ivfflat only supports relaxed_order; strict_order is for hnsw
+		setting = `SET LOCAL ivfflat.iterative_scan = relaxed_order`
+	}
+	if _, err := tx.ExecContext(ctx, setting); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var documents []VectorDocument
+	return scanRetrieved(rows, func(doc *RetrievedDocument) *float64 { return &doc.Scores.Similarity })
+}
+
+// keywordSearch finds the chunks in scope whose text contains any of the
+// query's words, best matches first. Stop words are ignored and words are
+// stemmed, so "certified" matches "certification".
+func (r *SimplePostgresChatbotRepository) keywordSearch(ctx context.Context, text string, scope SearchScope, limit int) ([]RetrievedDocument, error) {
+	conditions, args, err := scopeConditions(scope, []interface{}{text})
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, limit)
+
+	// plainto_tsquery requires every word to match, which a question phrased
+	// in full sentences rarely does, so require any of them instead
+	query := fmt.Sprintf(`
+		WITH search AS (
+			SELECT replace(plainto_tsquery('english', $1)::text, ' & ', ' | ')::tsquery AS query
+		)
+		SELECT id, content, metadata, ts_rank_cd(content_tsv, search.query) AS score
+		FROM vector_documents, search
+		WHERE content_tsv @@ search.query AND %s
+		ORDER BY score DESC
+		LIMIT $%d
+	`, conditions, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRetrieved(rows, func(doc *RetrievedDocument) *float64 { return &doc.Scores.KeywordScore })
+}
+
+// scopeConditions returns the WHERE conditions that limit a search to the
+// scope, appending their parameters to args
+func scopeConditions(scope SearchScope, args []interface{}) (string, []interface{}, error) {
+	if scope.UserID == "" {
+		return "", nil, errors.New("document search needs a user")
+	}
+
+	args = append(args, scope.UserID)
+	conditions := []string{fmt.Sprintf(`metadata->>'uploaded_by' = $%d`, len(args))}
+	if scope.SessionID != "" {
+		args = append(args, scope.SessionID)
+		conditions = append(conditions, fmt.Sprintf(`(metadata->>'session_id' IS NULL OR metadata->>'session_id' = $%d)`, len(args)))
+	}
+	if len(scope.Tags) > 0 {
+		args = append(args, pq.Array(scope.Tags))
+		conditions = append(conditions, fmt.Sprintf(`metadata->'tags' ?| $%d`, len(args)))
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// scanRetrieved reads rows of id, content, metadata and a score, storing the
+// score in the field that score points to
+func scanRetrieved(rows *sql.Rows, score func(*RetrievedDocument) *float64) ([]RetrievedDocument, error) {
+	var documents []RetrievedDocument
 	for rows.Next() {
-		var doc VectorDocument
+		var doc RetrievedDocument
 		var metadataBytes []byte
 
-		if err := rows.Scan(&doc.ID, &doc.Content, &metadataBytes); err != nil {
+		if err := rows.Scan(&doc.ID, &doc.Content, &metadataBytes, score(&doc)); err != nil {
 			return nil, err
 		}
 
@@ -259,208 +744,562 @@ func (r *SimplePostgresChatbotRepository) SearchSimilarDocuments(ctx context.Con
 		documents = append(documents, doc)
 	}
 
-	return documents, nil
+	return documents, rows.Err()
+}
+
+// preparedQuery is a prompt for the LLM with the documents that went into it
+type preparedQuery struct {
+	messages  []Message
+	documents []RetrievedDocument
+	question  ChatMessage // The unsaved user message, with the query embedding
+	report    ContextReport
+}
+
+// response builds the chat response for an answer to the query, mapping the
+// answer's citation markers back to the documents
+func (p preparedQuery) response(answer string) ChatResponse {
+	var sources []string
+	seen := map[string]bool{}
+	for _, doc := range p.documents {
+		if title := documentTitle(doc.VectorDocument); !seen[title] {
+			seen[title] = true
+			sources = append(sources, title)
+		}
+	}
+
+	return ChatResponse{
+		Answer:    answer,
+		Sources:   sources,
+		Citations: citations(answer, p.documents),
+		Metadata:  map[string]interface{}{"retrieval": chunkScores(p.documents), "context": p.report},
+		CreatedAt: time.Now(),
+	}
 }
 
-// callOpenRouter sends a request to Open Router API and returns the response
-func callOpenRouter(messages []Message) (string, error) {
-	// Get configuration for Open Router
-	cfg := config.LoadConfigFromEnv()
-	if cfg.OpenRouterAPIKey == "" || cfg.OpenRouterAPIKey == "your_openrouter_api_key" {
-		utils.Error("OPEN_ROUTER_API_KEY is not set or is using the default value. Please set a valid API key.")
-		return "I'm sorry, but my connection to the language model is not configured correctly. Please check your OPEN_ROUTER_API_KEY environment variable.", nil
+// prepareQuery builds the LLM prompt for a user message from the documents
+// retrieved in scope and the conversation history
+func (r *SimplePostgresChatbotRepository) prepareQuery(ctx context.Context, scope SearchScope, query string) (preparedQuery, error) {
+	sessionID := scope.SessionID
+	embedding, err := embedOne(ctx, r.embedder, query)
+	if err != nil {
+		// Without a query vector only the keyword search can run, and the
+		// model can still answer from the conversation
+		utils.Error("Failed to embed query: %v", err)
 	}
 
-	// Prepare request to Open Router API
-	requestData := OpenRouterRequest{
-		Model:       cfg.OpenRouterModel, // Default to a model specified in config
-		Messages:    messages,
-		MaxTokens:   1000, // Lower token limit to ensure it stays within free tier
-		Temperature: 0.7,  // Add temperature for more balanced responses
+	// The user message is only saved along with the answer
+	question := ChatMessage{
+		SessionID: sessionID,
+		Role:      "user",
+		Content:   query,
+		Embedding: embedding,
+		CreatedAt: time.Now(),
 	}
 
-	// Log model being used
-	utils.Info("Using Open Router model: %s with max_tokens: %d", cfg.OpenRouterModel, requestData.MaxTokens)
+	// Search for matching documents
+	docs, err := r.RetrieveDocuments(ctx, query, embedding, scope, 5)
+	if err != nil {
+		return preparedQuery{}, fmt.Errorf("failed to retrieve documents: %w", err)
+	}
 
-	requestBody, err := json.Marshal(requestData)
+	// Get previous conversation history
+	history, err := r.GetSessionMessages(ctx, sessionID)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		utils.Warning("Failed to get conversation history: %v", err)
+		// Continue with empty history
 	}
 
-	// Create HTTP request
-	req, err := http.NewRequest("POST", "https://openrouter.ai/api/v1/chat/completions", bytes.NewBuffer(requestBody))
+	// Older messages are replaced by the session summary
+	summary, through, err := r.sessionSummary(ctx, sessionID)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		utils.Warning("Failed to get session summary: %v", err)
+	}
+	history = messagesAfter(history, through)
+
+	// Fit the documents and history into the model's context window
+	built := r.contextBuilder.Build("You are a helpful assistant.", summary, docs, history, query)
+	if report := built.Report; len(report.DroppedDocuments)+len(report.TruncatedDocuments) > 0 || report.DroppedMessages > 0 {
+		utils.Info("Context for session %s left out %d documents and %d messages and truncated %d documents",
+			sessionID, len(report.DroppedDocuments), report.DroppedMessages, len(report.TruncatedDocuments))
+	}
+
+	return preparedQuery{messages: built.Messages, documents: built.Documents, question: question, report: built.Report}, nil
+}
+
+// saveTurn saves the query and its answer as one turn and starts updating
+// the session summary. A failure to save does not take the answer away from
+// the user, so it is only logged.
+func (r *SimplePostgresChatbotRepository) saveTurn(ctx context.Context, prepared preparedQuery, response string) {
+	answer := ChatMessage{
+		SessionID: prepared.question.SessionID,
+		Role:      "assistant",
+		Content:   response,
+		CreatedAt: time.Now(),
 	}
 
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+cfg.OpenRouterAPIKey)
-	req.Header.Set("HTTP-Referer", "https://resume.in") // Replace with your actual domain
-	req.Header.Set("X-Title", "Resume.in Chatbot")
+	if _, _, err := r.SaveTurn(ctx, prepared.question, answer); err != nil {
+		utils.Error("Failed to save chat turn: %v", err)
+		return
+	}
+	r.summarizeInBackground(prepared.question.SessionID)
+}
 
-	// Send request
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+// ProcessQuery processes a user query and returns a response from the LLM.
+// The response metadata lists the retrieved chunks with their scores.
+func (r *SimplePostgresChatbotRepository) ProcessQuery(ctx context.Context, scope SearchScope, query string) (ChatResponse, error) {
+	prepared, err := r.prepareQuery(ctx, scope, query)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return ChatResponse{}, err
 	}
-	defer resp.Body.Close()
 
-	// Read response
-	body, err := io.ReadAll(resp.Body)
+	// Call the LLM with the client's default options
+	response, err := r.llm.Complete(ctx, prepared.messages, CompletionOptions{})
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		utils.Error("Failed to get response from LLM: %v", err)
+		return ChatResponse{}, ErrLLMUnavailable
 	}
 
-	// Parse response
-	var openRouterResp OpenRouterResponse
-	if err := json.Unmarshal(body, &openRouterResp); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+	r.saveTurn(ctx, prepared, response)
+
+	// Return the response
+	return prepared.response(response), nil
+}
+
+// ProcessQueryStream works like ProcessQuery but passes the answer to onDelta
+// as the LLM generates it. The turn is saved once the stream ends, keeping a
+// partial answer if the stream broke off after the client saw part of it,
+// unless the LLM timed out, which is reported as ErrLLMUnavailable instead.
+// If ctx is cancelled, for example because the client went away, the upstream
+// request is aborted and nothing is saved.
+func (r *SimplePostgresChatbotRepository) ProcessQueryStream(ctx context.Context, scope SearchScope, query string, onDelta StreamHandler) (ChatResponse, error) {
+	prepared, err := r.prepareQuery(ctx, scope, query)
+	if err != nil {
+		return ChatResponse{}, err
 	}
 
-	// Check for errors
-	if openRouterResp.Error != nil {
-		return "", fmt.Errorf("open router error: %s", openRouterResp.Error.Message)
+	response, err := r.llm.Stream(ctx, prepared.messages, CompletionOptions{}, onDelta)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		utils.Warning("Stream for session %s cancelled: %v", scope.SessionID, ctxErr)
+		return ChatResponse{}, ctxErr
+	}
+	if err != nil {
+		utils.Error("Failed to stream response from LLM: %v", err)
+		if response == "" || errors.Is(err, ErrLLMTimeout) {
+			return ChatResponse{}, ErrLLMUnavailable
+		}
+		// Otherwise keep the partial answer the client has already seen
 	}
 
-	// Check if we have valid choices
-	if len(openRouterResp.Choices) == 0 {
-		return "", fmt.Errorf("no response from LLM")
+	r.saveTurn(ctx, prepared, response)
+
+	return prepared.response(response), nil
+}
+
+// summaryTimeout limits a background summary update
+const summaryTimeout = 2 * time.Minute
+
+// sessionSummary returns a session's summary and the ID of the last message it covers
+func (r *SimplePostgresChatbotRepository) sessionSummary(ctx context.Context, sessionID string) (string, int64, error) {
+	var summary string
+	var through int64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT summary, summary_message_id FROM chat_sessions WHERE id = $1
+	`, sessionID).Scan(&summary, &through)
+	if err == sql.ErrNoRows {
+		return "", 0, nil
 	}
+	return summary, through, err
+}
 
-	// Return the content of the first choice
-	return openRouterResp.Choices[0].Message.Content, nil
+// summarizeInBackground updates the session summary without holding up the
+// reply. The request context may be cancelled as soon as the reply is sent,
+// so the update gets its own.
+func (r *SimplePostgresChatbotRepository) summarizeInBackground(sessionID string) {
+	if r.summarizer == nil {
+		return
+	}
+	if _, running := r.summarizing.LoadOrStore(sessionID, true); running {
+		return
+	}
+	go func() {
+		defer r.summarizing.Delete(sessionID)
+		ctx, cancel := context.WithTimeout(context.Background(), summaryTimeout)
+		defer cancel()
+		if err := r.summarizeSession(ctx, sessionID); err != nil {
+			utils.Warning("Failed to update summary of session %s: %v", sessionID, err)
+		}
+	}()
 }
 
-// ProcessQuery processes a user query and returns a response using Open Router
-func (r *SimplePostgresChatbotRepository) ProcessQuery(ctx context.Context, sessionID string, query string) (ChatResponse, error) {
-	// Create a simple embedding (in a real implementation, this would use an embedding model)
-	embedding := make([]float32, 1536)
-	for i := range embedding {
-		embedding[i] = float32(i % 100) / 100.0
+// summarizeSession folds older messages into the session summary once
+// enough of them have piled up since it was last updated
+func (r *SimplePostgresChatbotRepository) summarizeSession(ctx context.Context, sessionID string) error {
+	summary, through, err := r.sessionSummary(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	history, err := r.GetSessionMessages(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	older := r.summarizer.due(history, through)
+	if len(older) == 0 {
+		return nil
 	}
 
-	// Save the user message
-	userMsg := ChatMessage{
-		SessionID: sessionID,
-		Role:      "user",
-		Content:   query,
-		Embedding: embedding,
-		CreatedAt: time.Now(),
+	updated, err := r.summarizer.Update(ctx, summary, older)
+	if err != nil {
+		return err
 	}
-	
-	_, err := r.SaveMessage(ctx, userMsg)
+
+	// Only store the summary if no other update got there first
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE chat_sessions
+		SET summary = $2, summary_message_id = $3
+		WHERE id = $1 AND summary_message_id = $4
+	`, sessionID, updated, older[len(older)-1].ID, through)
 	if err != nil {
-		utils.Error("Failed to save user message: %v", err)
-		// Continue processing anyway
+		return err
+	}
+
+	utils.Info("Summarized %d messages of session %s", len(older), sessionID)
+	return nil
+}
+
+// messagesAfter returns the messages saved after the one with the given ID
+func messagesAfter(messages []ChatMessage, id int64) []ChatMessage {
+	var after []ChatMessage
+	for _, msg := range messages {
+		if msg.ID > id {
+			after = append(after, msg)
+		}
 	}
+	return after
+}
+
+// ExtractResume builds structured resume data from a conversation with the LLM
+func (r *SimplePostgresChatbotRepository) ExtractResume(ctx context.Context, messages []ChatMessage) (Resume, error) {
+	return NewResumeExtractor(r.llm).Extract(ctx, messages)
+}
+
+// embedBatchSize is the number of texts sent to the embedder at once
+const embedBatchSize = 32
+
+// chunkInsertBatchSize is the number of chunks written per INSERT statement
+const chunkInsertBatchSize = 100
 
-	// Search for similar documents
-	docs, err := r.SearchSimilarDocuments(ctx, embedding, 5)
+// AddDocument splits a document into chunks and stores each as a vector
+// document with the document ID as parent_id and the user as uploaded_by in
+// its metadata. When replacing a document, the new chunks replace the old
+// ones in one transaction, so searches never see a mix of the two.
+func (r *SimplePostgresChatbotRepository) AddDocument(ctx context.Context, userID, documentID, content string, metadata map[string]interface{}) (string, int, error) {
+	chunks := r.chunker.Split(content)
+	if len(chunks) == 0 {
+		return "", 0, ErrDocumentEmpty
+	}
+	replace := documentID != ""
+	if !replace {
+		documentID = uuid.New().String()
+	}
+	metadata, err := r.scopeMetadata(ctx, userID, metadata)
 	if err != nil {
-		return ChatResponse{}, fmt.Errorf("failed to search similar documents: %w", err)
+		return "", 0, err
 	}
+	metadata = withMetadata(metadata, "uploaded_by", userID)
 
-	// Prepare context for LLM from retrieved documents
-	var context string
-	var sources []string
-	
-	if len(docs) > 0 {
-		context = "Context information:\n\n"
-		for _, doc := range docs {
-			context += doc.Content + "\n\n"
-			if doc.Metadata != nil {
-				if source, ok := doc.Metadata["source"].(string); ok {
-					sources = append(sources, source)
-				}
-			}
+	// Embed before opening the transaction, since it can take a while
+	embeddings := make([][]float32, 0, len(chunks))
+	for start := 0; start < len(chunks); start += embedBatchSize {
+		end := min(start+embedBatchSize, len(chunks))
+		texts := make([]string, 0, end-start)
+		for _, chunk := range chunks[start:end] {
+			texts = append(texts, chunk.Content)
+		}
+		vectors, err := r.embedder.Embed(ctx, texts)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to embed document: %w", err)
 		}
+		embeddings = append(embeddings, vectors...)
 	}
 
-	// Get previous conversation history
-	history, err := r.GetSessionMessages(ctx, sessionID)
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		utils.Warning("Failed to get conversation history: %v", err)
-		// Continue with empty history
+		return "", 0, err
 	}
+	defer tx.Rollback()
 
-	// Prepare messages for Open Router
-	var messages []Message
-	
-	// System message with context
-	messages = append(messages, Message{
-		Role:    "system",
-		Content: "You are a helpful assistant. Use the following context information to answer the user's question, if relevant: " + context,
-	})
+	if replace {
+		// Lock the old chunks so concurrent replacements wait for each other
+		var owners []string
+		err := tx.SelectContext(ctx, &owners, `
+			SELECT COALESCE(metadata->>'uploaded_by', '')
+			FROM vector_documents
+			WHERE metadata->>'parent_id' = $1
+			FOR UPDATE
+		`, documentID)
+		if err != nil {
+			return "", 0, err
+		}
+		if len(owners) == 0 {
+			return "", 0, ErrDocumentNotFound
+		}
+		if owners[0] != userID {
+			return "", 0, ErrDocumentForbidden
+		}
 
-	// Add conversation history (up to 10 messages to avoid token limits)
-	maxHistory := 10
-	if len(history) > maxHistory {
-		history = history[len(history)-maxHistory:]
+		if _, err := tx.ExecContext(ctx, `DELETE FROM vector_documents WHERE metadata->>'parent_id' = $1`, documentID); err != nil {
+			return "", 0, fmt.Errorf("failed to remove old chunks: %w", err)
+		}
 	}
-	
-	for _, msg := range history {
-		messages = append(messages, Message{
-			Role:    msg.Role,
-			Content: msg.Content,
-		})
+
+	for start := 0; start < len(chunks); start += chunkInsertBatchSize {
+		end := min(start+chunkInsertBatchSize, len(chunks))
+
+		var query strings.Builder
+		query.WriteString(`INSERT INTO vector_documents (id, content, metadata, embedding) VALUES `)
+		args := make([]interface{}, 0, 4*(end-start))
+		for i, chunk := range chunks[start:end] {
+			chunkMeta, err := json.Marshal(chunkMetadata(metadata, documentID, chunk, len(chunks)))
+			if err != nil {
+				return "", 0, err
+			}
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			n := len(args)
+			fmt.Fprintf(&query, "($%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4)
+			args = append(args, chunkID(documentID, chunk.Index), chunk.Content, chunkMeta, pgvector.NewVector(embeddings[chunk.Index]))
+		}
+
+		if _, err := tx.ExecContext(ctx, query.String(), args...); err != nil {
+			return "", 0, fmt.Errorf("failed to store chunks: %w", err)
+		}
 	}
 
-	// Add the current query if not already in history
-	if len(history) == 0 || history[len(history)-1].Content != query {
-		messages = append(messages, Message{
-			Role:    "user",
-			Content: query,
-		})
+	if err := tx.Commit(); err != nil {
+		return "", 0, err
 	}
+	return documentID, len(chunks), nil
+}
 
-	// Call Open Router API
-	response, err := callOpenRouter(messages)
+// ListDocuments returns the user's knowledge base documents, most recently
+// uploaded first. Their chunks are left out.
+func (r *SimplePostgresChatbotRepository) ListDocuments(ctx context.Context, userID string) ([]KnowledgeDocument, error) {
+	// Every document has exactly one first chunk, which carries the chunk count
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT metadata, created_at
+		FROM vector_documents
+		WHERE metadata->>'uploaded_by' = $1 AND metadata->>'chunk_index' = '0'
+		ORDER BY created_at DESC
+	`, userID)
 	if err != nil {
-		utils.Error("Failed to get response from Open Router: %v", err)
-		response = "I'm sorry, I'm having trouble processing your request right now. Please try again later."
+		return nil, err
 	}
+	defer rows.Close()
 
-	// Save the assistant message - make sure to include the embedding
-	assistantMsg := ChatMessage{
-		SessionID: sessionID,
-		Role:      "assistant",
-		Content:   response,
-		Embedding: embedding, // Use the same embedding as the query for simplicity
-		CreatedAt: time.Now(),
+	documents := []KnowledgeDocument{}
+	for rows.Next() {
+		var metadataBytes []byte
+		var uploadedAt time.Time
+		if err := rows.Scan(&metadataBytes, &uploadedAt); err != nil {
+			return nil, err
+		}
+		document, err := knowledgeDocument(metadataBytes)
+		if err != nil {
+			return nil, err
+		}
+		document.UploadedAt = uploadedAt
+		documents = append(documents, document)
 	}
-	
-	_, err = r.SaveMessage(ctx, assistantMsg)
+
+	return documents, rows.Err()
+}
+
+// GetDocument retrieves a document owned by the user with all its chunks
+func (r *SimplePostgresChatbotRepository) GetDocument(ctx context.Context, userID, documentID string) (KnowledgeDocument, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, content, metadata, created_at
+		FROM vector_documents
+		WHERE metadata->>'parent_id' = $1
+		ORDER BY (metadata->>'chunk_index')::int
+	`, documentID)
 	if err != nil {
-		utils.Error("Failed to save assistant message: %v", err)
-		// Continue anyway
+		return KnowledgeDocument{}, err
 	}
+	defer rows.Close()
 
-	// Return the response
-	return ChatResponse{
-		Answer:    response,
-		Sources:   sources,
-		CreatedAt: time.Now(),
-	}, nil
+	var document KnowledgeDocument
+	for rows.Next() {
+		var chunk DocumentChunk
+		var metadataBytes []byte
+		var uploadedAt time.Time
+		if err := rows.Scan(&chunk.ID, &chunk.Content, &metadataBytes, &uploadedAt); err != nil {
+			return KnowledgeDocument{}, err
+		}
+
+		if document.ID == "" {
+			if document, err = knowledgeDocument(metadataBytes); err != nil {
+				return KnowledgeDocument{}, err
+			}
+			document.UploadedAt = uploadedAt
+		}
+
+		var position struct {
+			Index  int `json:"chunk_index"`
+			Offset int `json:"offset"`
+		}
+		if err := json.Unmarshal(metadataBytes, &position); err != nil {
+			return KnowledgeDocument{}, err
+		}
+		chunk.Index, chunk.Offset = position.Index, position.Offset
+		document.Chunks = append(document.Chunks, chunk)
+	}
+	if err := rows.Err(); err != nil {
+		return KnowledgeDocument{}, err
+	}
+
+	if document.ID == "" {
+		return KnowledgeDocument{}, ErrDocumentNotFound
+	}
+	if document.UserID != userID {
+		return KnowledgeDocument{}, ErrDocumentForbidden
+	}
+	return document, nil
 }
 
-// AddDocument is a helper function that prepares and stores a document
-func (r *SimplePostgresChatbotRepository) AddDocument(ctx context.Context, content string, metadata map[string]interface{}) error {
-	// Create a simple embedding (in a real implementation, this would use an embedding model)
-	embedding := make([]float32, 1536)
-	for i := range embedding {
-		embedding[i] = float32(i % 100) / 100.0
+// DeleteDocument removes a document owned by the user with all its chunks
+func (r *SimplePostgresChatbotRepository) DeleteDocument(ctx context.Context, userID, documentID string) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM vector_documents
+		WHERE metadata->>'parent_id' = $1 AND metadata->>'uploaded_by' = $2
+	`, documentID, userID)
+	if err != nil {
+		return err
 	}
 
-	doc := VectorDocument{
-		ID:        uuid.New().String(),
-		Content:   content,
-		Metadata:  metadata,
-		Embedding: embedding,
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		// Tell a missing document apart from someone else's
+		var exists bool
+		err := r.db.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM vector_documents WHERE metadata->>'parent_id' = $1)
+		`, documentID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrDocumentForbidden
+		}
+		return ErrDocumentNotFound
 	}
+	return nil
+}
+
+// DeleteDocuments removes every document owned by the user with all their
+// chunks, and returns the number of documents removed
+func (r *SimplePostgresChatbotRepository) DeleteDocuments(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		WITH deleted AS (
+			DELETE FROM vector_documents
+			WHERE metadata->>'uploaded_by' = $1
+			RETURNING metadata->>'parent_id' AS parent_id
+		)
+		SELECT COUNT(DISTINCT parent_id) FROM deleted
+	`, userID).Scan(&count)
+	return count, err
+}
 
-	return r.StoreDocument(ctx, doc)
+// scopeMetadata checks the metadata that scopes searches for a document: a
+// session_id must name one of the user's chat sessions, and tags must be a
+// list of strings. Tags are trimmed, and blank and repeated ones dropped.
+func (r *SimplePostgresChatbotRepository) scopeMetadata(ctx context.Context, userID string, metadata map[string]interface{}) (map[string]interface{}, error) {
+	if value, ok := metadata["session_id"]; ok {
+		sessionID, ok := value.(string)
+		if !ok {
+			return nil, ErrDocumentMetadata
+		}
+		if _, err := r.GetSession(ctx, userID, sessionID); err != nil {
+			return nil, err
+		}
+	}
+
+	value, ok := metadata["tags"]
+	if !ok {
+		return metadata, nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, ErrDocumentMetadata
+	}
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, item := range list {
+		tag, ok := item.(string)
+		if !ok {
+			return nil, ErrDocumentMetadata
+		}
+		if tag = strings.TrimSpace(tag); tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return withMetadata(metadata, "tags", tags), nil
+}
+
+// knowledgeDocument builds a document from the metadata of one of its chunks
+func knowledgeDocument(metadataBytes []byte) (KnowledgeDocument, error) {
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
+		return KnowledgeDocument{}, err
+	}
+
+	document := KnowledgeDocument{Metadata: map[string]interface{}{}}
+	document.ID, _ = metadata["parent_id"].(string)
+	document.UserID, _ = metadata["uploaded_by"].(string)
+	if count, ok := metadata["chunk_count"].(float64); ok {
+		document.ChunkCount = int(count)
+	}
+	for key, value := range metadata {
+		switch key {
+		case "parent_id", "chunk_index", "chunk_count", "offset":
+		default:
+			document.Metadata[key] = value
+		}
+	}
+	return document, nil
+}
+
+// withMetadata returns a copy of metadata with key set to value
+func withMetadata(metadata map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		result[k] = v
+	}
+	result[key] = value
+	return result
+}
+
+// chunkID returns the vector document ID of a chunk
+func chunkID(documentID string, index int) string {
+	return fmt.Sprintf("%s_%d", documentID, index)
+}
+
+// chunkMetadata returns the metadata stored with a chunk: the document's
+// metadata plus where the chunk sits in it
+func chunkMetadata(metadata map[string]interface{}, documentID string, chunk Chunk, count int) map[string]interface{} {
+	result := make(map[string]interface{}, len(metadata)+4)
+	for key, value := range metadata {
+		result[key] = value
+	}
+	result["parent_id"] = documentID
+	result["chunk_index"] = chunk.Index
+	result["chunk_count"] = count
+	result["offset"] = chunk.Offset
+	return result
 }
 
 // Helper function for min
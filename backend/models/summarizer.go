@@ -0,0 +1,76 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxSummaryTokens caps a session summary, so that it never crowds the
+// documents and recent messages out of the prompt
+const maxSummaryTokens = 800
+
+// summaryPrompt is the system prompt for updating a session summary
+const summaryPrompt = `You keep a running summary of a conversation between a user and an assistant that helps them
+build their resume. You get the current summary, which may be empty, and the messages that followed it.
+
+Reply with the updated summary and nothing else. Rules:
+- Keep every fact the user stated about themselves: name, contact details, jobs with companies, titles and dates,
+  education, skills, certificates, projects, goals and preferences. Never drop them from the current summary.
+- Correct facts the user corrected, and note open questions or tasks the assistant agreed to.
+- Leave out small talk and the assistant's general advice.
+- Write in the third person ("The user ...") in at most 300 words.`
+
+// Summarizer condenses older messages of a chat session into a summary.
+// Sessions are summarized once after messages have piled up since the
+// summary, folding in all but the latest keep of them. Doing it in batches
+// keeps the number of LLM calls down.
+type Summarizer struct {
+	llm   LLMClient
+	after int
+	keep  int
+}
+
+// NewSummarizer creates a summarizer that uses llm. keep must be less than after.
+func NewSummarizer(llm LLMClient, after, keep int) *Summarizer {
+	return &Summarizer{llm: llm, after: after, keep: keep}
+}
+
+// due returns the messages of history to fold into a summary that covers
+// messages up to the one with ID through, or nil while fewer than after
+// messages have piled up since it
+func (s *Summarizer) due(history []ChatMessage, through int64) []ChatMessage {
+	pending := messagesAfter(history, through)
+	if len(pending) < s.after {
+		return nil
+	}
+	return pending[:len(pending)-s.keep]
+}
+
+// Update returns the summary extended with the messages that followed it
+func (s *Summarizer) Update(ctx context.Context, summary string, messages []ChatMessage) (string, error) {
+	var transcript strings.Builder
+	for _, msg := range messages {
+		fmt.Fprintf(&transcript, "%s: %s\n\n", msg.Role, msg.Content)
+	}
+
+	current := summary
+	if current == "" {
+		current = "(none yet)"
+	}
+
+	reply, err := s.llm.Complete(ctx, []Message{
+		{Role: "system", Content: summaryPrompt},
+		{Role: "user", Content: "Current summary:\n\n" + current + "\n\nNew messages:\n\n" + transcript.String()},
+	}, CompletionOptions{Temperature: Float64(0.1)})
+	if err != nil {
+		return "", fmt.Errorf("failed to get summary from LLM: %w", err)
+	}
+
+	reply = strings.TrimSpace(reply)
+	if reply == "" {
+		return "", errors.New("LLM returned an empty summary")
+	}
+	return truncateToTokens(reply, maxSummaryTokens), nil
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// history returns a session history whose messages have the given IDs, in order
+func history(ids ...int64) []ChatMessage {
+	messages := make([]ChatMessage, 0, len(ids))
+	for i, id := range ids {
+		role := "user"
+		if i%2 == 1 {
+			role = "assistant"
+		}
+		messages = append(messages, ChatMessage{ID: id, Role: role, Content: "message"})
+	}
+	return messages
+}
+
+// messageIDs lists the IDs of messages in order
+func messageIDs(messages []ChatMessage) []int64 {
+	var result []int64
+	for _, msg := range messages {
+		result = append(result, msg.ID)
+	}
+	return result
+}
+
+func TestSummarizerDue(t *testing.T) {
+	tests := []struct {
+		name        string
+		after, keep int
+		history     []ChatMessage
+		through     int64 // Last message the summary covers, 0 for none
+		want        []int64
+	}{
+		{
+			name:  "fewer messages than the threshold",
+			after: 6, keep: 2,
+			history: history(1, 2, 3, 4, 5),
+		},
+		{
+			name:  "the threshold summarizes all but the kept messages",
+			after: 6, keep: 2,
+			history: history(1, 2, 3, 4, 5, 6),
+			want:    []int64{1, 2, 3, 4},
+		},
+		{
+			name:  "nothing is kept out",
+			after: 4, keep: 0,
+			history: history(1, 2, 3, 4),
+			want:    []int64{1, 2, 3, 4},
+		},
+		{
+			name:  "only messages after the summary count",
+			after: 6, keep: 2,
+			history: history(1, 2, 3, 4, 5, 6, 7, 8, 9),
+			through: 4,
+		},
+		{
+			name:  "messages after the summary reach the threshold",
+			after: 6, keep: 2,
+			history: history(1, 2, 3, 4, 5, 6, 7, 8, 9, 10),
+			through: 4,
+			want:    []int64{5, 6, 7, 8},
+		},
+		{
+			name:  "IDs need not be consecutive",
+			after: 4, keep: 1,
+			history: history(3, 8, 21, 22, 40, 41),
+			through: 8,
+			want:    []int64{21, 22, 40},
+		},
+		{
+			name:  "the summary covers everything",
+			after: 2, keep: 1,
+			history: history(1, 2),
+			through: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summarizer := NewSummarizer(NewFakeLLMClient(), tt.after, tt.keep)
+			if got := messageIDs(summarizer.due(tt.history, tt.through)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("due() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizerUpdate(t *testing.T) {
+	long := strings.Repeat("The user worked at Acme. ", 500)
+
+	tests := []struct {
+		name       string
+		summary    string
+		reply      ScriptedResponse
+		want       string
+		wantPrompt []string // Parts the user prompt must contain
+		wantErr    bool
+	}{
+		{
+			name:       "first summary",
+			reply:      ScriptedResponse{Content: "  The user is Ada, a mathematician.\n"},
+			want:       "The user is Ada, a mathematician.",
+			wantPrompt: []string{"Current summary:\n\n(none yet)", "user: I'm Ada.", "assistant: Nice to meet you."},
+		},
+		{
+			name:       "the current summary is extended",
+			summary:    "The user is Ada.",
+			reply:      ScriptedResponse{Content: "The user is Ada, a mathematician."},
+			want:       "The user is Ada, a mathematician.",
+			wantPrompt: []string{"Current summary:\n\nThe user is Ada.\n\nNew messages:"},
+		},
+		{
+			name:  "long summaries are cut",
+			reply: ScriptedResponse{Content: long},
+			want:  truncateToTokens(strings.TrimSpace(long), maxSummaryTokens),
+		},
+		{
+			name:    "an empty summary is an error",
+			reply:   ScriptedResponse{Content: " \n"},
+			wantErr: true,
+		},
+		{
+			name:    "LLM errors are returned",
+			reply:   ScriptedResponse{Err: errors.New("unavailable")},
+			wantErr: true,
+		},
+	}
+
+	messages := []ChatMessage{{Role: "user", Content: "I'm Ada."}, {Role: "assistant", Content: "Nice to meet you."}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := NewFakeLLMClient()
+			llm.Script(tt.reply)
+
+			got, err := NewSummarizer(llm, 20, 10).Update(context.Background(), tt.summary, messages)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Update() = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Update() = %q, want %q", got, tt.want)
+			}
+			if EstimateTokens(got) > maxSummaryTokens {
+				t.Errorf("Update() = %d tokens, over the limit of %d", EstimateTokens(got), maxSummaryTokens)
+			}
+
+			calls := llm.Calls()
+			if len(calls) != 1 || len(calls[0].Messages) != 2 || calls[0].Messages[0].Content != summaryPrompt {
+				t.Fatalf("Update() made calls %+v, want one with the summary prompt", calls)
+			}
+			for _, part := range tt.wantPrompt {
+				if !strings.Contains(calls[0].Messages[1].Content, part) {
+					t.Errorf("prompt %q does not contain %q", calls[0].Messages[1].Content, part)
+				}
+			}
+		})
+	}
+}
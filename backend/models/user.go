@@ -2,9 +2,13 @@ package models
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrUserNotFound is returned when no user matches a lookup
+var ErrUserNotFound = errors.New("user not found")
+
 // User represents a user in the system
 type User struct {
 	ID          string    `json:"id" db:"id"`
@@ -15,6 +19,8 @@ type User struct {
 	ProviderID  string    `json:"provider_id,omitempty" db:"provider_id"`        // ID from the OAuth provider
 	Picture     string    `json:"picture,omitempty" db:"picture"`
 	Role        string    `json:"role" db:"role"`           // user, admin
+	FailedLoginAttempts int        `json:"-" db:"failed_login_attempts"` // consecutive failed password logins
+	LockedUntil         *time.Time `json:"-" db:"locked_until"`          // password login is refused until this time
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -27,4 +33,6 @@ type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	GetByProviderID(ctx context.Context, provider, providerID string) (*User, error)
 	Delete(ctx context.Context, id string) error
+	RecordFailedLogin(ctx context.Context, id string, maxAttempts int, lockDuration time.Duration) (*time.Time, error)
+	ResetFailedLogins(ctx context.Context, id string) error
 } 
\ No newline at end of file
@@ -3,7 +3,6 @@ package models
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -49,7 +48,8 @@ func (r *PostgresUserRepository) Create(ctx context.Context, user *User) error {
 // GetByID retrieves a user by ID
 func (r *PostgresUserRepository) GetByID(ctx context.Context, id string) (*User, error) {
 	query := `
-		SELECT id, email, name, password, provider, provider_id, picture, role, created_at, updated_at
+		SELECT id, email, name, password, provider, provider_id, picture, role,
+			failed_login_attempts, locked_until, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -58,7 +58,7 @@ func (r *PostgresUserRepository) GetByID(ctx context.Context, id string) (*User,
 	err := r.db.GetContext(ctx, &user, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -69,7 +69,8 @@ func (r *PostgresUserRepository) GetByID(ctx context.Context, id string) (*User,
 // GetByEmail retrieves a user by email
 func (r *PostgresUserRepository) GetByEmail(ctx context.Context, email string) (*User, error) {
 	query := `
-		SELECT id, email, name, password, provider, provider_id, picture, role, created_at, updated_at
+		SELECT id, email, name, password, provider, provider_id, picture, role,
+			failed_login_attempts, locked_until, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -78,7 +79,7 @@ func (r *PostgresUserRepository) GetByEmail(ctx context.Context, email string) (
 	err := r.db.GetContext(ctx, &user, query, email)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -89,7 +90,8 @@ func (r *PostgresUserRepository) GetByEmail(ctx context.Context, email string) (
 // GetByProviderID retrieves a user by provider and provider ID
 func (r *PostgresUserRepository) GetByProviderID(ctx context.Context, provider, providerID string) (*User, error) {
 	query := `
-		SELECT id, email, name, password, provider, provider_id, picture, role, created_at, updated_at
+		SELECT id, email, name, password, provider, provider_id, picture, role,
+			failed_login_attempts, locked_until, created_at, updated_at
 		FROM users
 		WHERE provider = $1 AND provider_id = $2
 	`
@@ -98,7 +100,7 @@ func (r *PostgresUserRepository) GetByProviderID(ctx context.Context, provider,
 	err := r.db.GetContext(ctx, &user, query, provider, providerID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -143,12 +145,52 @@ func (r *PostgresUserRepository) Delete(ctx context.Context, id string) error {
 	}
 	
 	if rowsAffected == 0 {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
 	
 	return nil
 }
 
+// RecordFailedLogin increments the failed login counter for a user. Once the
+// counter reaches maxAttempts the account is locked for lockDuration and the
+// counter starts over. It returns the lock expiry, or nil if the account is not locked.
+func (r *PostgresUserRepository) RecordFailedLogin(ctx context.Context, id string, maxAttempts int, lockDuration time.Duration) (*time.Time, error) {
+	query := `
+		UPDATE users
+		SET failed_login_attempts = CASE WHEN failed_login_attempts + 1 >= $2 THEN 0 ELSE failed_login_attempts + 1 END,
+			locked_until = CASE WHEN failed_login_attempts + 1 >= $2 THEN $3 ELSE locked_until END
+		WHERE id = $1
+		RETURNING locked_until
+	`
+	
+	var lockedUntil sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, id, maxAttempts, time.Now().Add(lockDuration)).Scan(&lockedUntil)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	
+	if !lockedUntil.Valid {
+		return nil, nil
+	}
+	
+	return &lockedUntil.Time, nil
+}
+
+// ResetFailedLogins clears the failed login counter and any lock on a user
+func (r *PostgresUserRepository) ResetFailedLogins(ctx context.Context, id string) error {
+	query := `
+		UPDATE users
+		SET failed_login_attempts = 0, locked_until = NULL
+		WHERE id = $1
+	`
+	
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
 // CreateUserTable creates the users table if it doesn't exist
 func CreateUserTable(db *sqlx.DB) error {
 	query := `
@@ -161,6 +203,8 @@ func CreateUserTable(db *sqlx.DB) error {
 			provider_id VARCHAR(255),
 			picture TEXT,
 			role VARCHAR(50) NOT NULL DEFAULT 'user',
+			failed_login_attempts INTEGER NOT NULL DEFAULT 0,
+			locked_until TIMESTAMP,
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			UNIQUE(provider, provider_id)
@@ -0,0 +1,225 @@
+package render
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"resume.in/backend/models"
+)
+
+// DOCX writes the resume as a Word document. It follows the section order of
+// the classic template and uses Word's built-in Title, Heading and List Bullet
+// styles, so ATS parsers can tell headings, entries and bullets apart.
+func DOCX(w io.Writer, resume models.Resume) error {
+	body := &docxBody{}
+	writeDOCXBody(body, resume)
+
+	zw := zip.NewWriter(w)
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRels},
+		{"docProps/core.xml", docxCoreProps(resume.BasicInfo.Name)},
+		{"word/_rels/document.xml.rels", docxDocumentRels},
+		{"word/styles.xml", docxStyles},
+		{"word/numbering.xml", docxNumbering},
+		{"word/document.xml", docxDocumentStart + body.String() + docxDocumentEnd},
+	} {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// writeDOCXBody writes the document paragraphs
+func writeDOCXBody(b *docxBody, r models.Resume) {
+	b.para("Title", docxRun{text: r.BasicInfo.Name})
+
+	info := r.BasicInfo
+	for _, line := range []struct{ label, value string }{
+		{"Email", info.Email},
+		{"Phone", info.Phone},
+		{"Address", info.Address},
+		{"Website", info.Website},
+		{"LinkedIn", info.LinkedIn},
+		{"GitHub", info.GitHub},
+	} {
+		if line.value != "" {
+			b.para("", docxRun{text: line.label + ": ", bold: true}, docxRun{text: line.value})
+		}
+	}
+
+	if r.Summary != "" {
+		b.para("Heading1", docxRun{text: "Professional Summary"})
+		b.para("", docxRun{text: r.Summary})
+	}
+	if len(r.Experience) > 0 {
+		b.para("Heading1", docxRun{text: "Experience"})
+		for _, exp := range r.Experience {
+			b.para("Heading2", docxRun{text: joinNonEmpty(" | ", exp.Position, exp.Company)})
+			b.para("", docxRun{text: joinNonEmpty(" - ", exp.StartDate, exp.EndDate), italic: true})
+			b.para("", docxRun{text: exp.Description})
+			for _, highlight := range exp.Highlights {
+				b.para("ListBullet", docxRun{text: highlight})
+			}
+		}
+	}
+	if len(r.Education) > 0 {
+		b.para("Heading1", docxRun{text: "Education"})
+		for _, edu := range r.Education {
+			b.para("Heading2", docxRun{text: joinNonEmpty(" in ", edu.Degree, edu.Field)})
+			b.para("", docxRun{text: edu.Institution})
+			b.para("", docxRun{text: joinNonEmpty(" - ", edu.StartDate, edu.EndDate), italic: true})
+			if edu.GPA != "" {
+				b.para("", docxRun{text: "GPA: " + edu.GPA})
+			}
+		}
+	}
+	if len(r.Skills) > 0 {
+		b.para("Heading1", docxRun{text: "Skills"})
+		for _, skill := range r.Skills {
+			b.para("ListBullet", docxRun{text: skillLabel(skill)})
+		}
+	}
+	if len(r.Certificates) > 0 {
+		b.para("Heading1", docxRun{text: "Certifications"})
+		for _, cert := range r.Certificates {
+			b.para("Heading2", docxRun{text: cert.Name})
+			b.para("", docxRun{text: joinNonEmpty(" | ", cert.Issuer, cert.IssueDate, cert.URL)})
+		}
+	}
+	if len(r.Projects) > 0 {
+		b.para("Heading1", docxRun{text: "Projects"})
+		for _, project := range r.Projects {
+			b.para("Heading2", docxRun{text: project.Name})
+			b.para("", docxRun{text: joinNonEmpty(" - ", project.StartDate, project.EndDate), italic: true})
+			b.para("", docxRun{text: project.Description})
+			if len(project.Technologies) > 0 {
+				b.para("", docxRun{text: "Technologies: ", bold: true}, docxRun{text: strings.Join(project.Technologies, ", ")})
+			}
+			b.para("", docxRun{text: project.URL})
+		}
+	}
+}
+
+// docxRun is a stretch of text with one set of formatting
+type docxRun struct {
+	text         string
+	bold, italic bool
+}
+
+// docxBody collects the paragraphs of word/document.xml
+type docxBody struct {
+	bytes.Buffer
+}
+
+// para writes a paragraph in the given style ("" for Normal). Paragraphs
+// without text are skipped.
+func (b *docxBody) para(style string, runs ...docxRun) {
+	empty := true
+	for _, r := range runs {
+		if strings.TrimSpace(r.text) != "" {
+			empty = false
+		}
+	}
+	if empty {
+		return
+	}
+
+	b.WriteString("<w:p>")
+	if style != "" {
+		b.WriteString(`<w:pPr><w:pStyle w:val="` + style + `"/></w:pPr>`)
+	}
+	for _, r := range runs {
+		if r.text == "" {
+			continue
+		}
+		b.WriteString("<w:r>")
+		if r.bold || r.italic {
+			b.WriteString("<w:rPr>")
+			if r.bold {
+				b.WriteString("<w:b/>")
+			}
+			if r.italic {
+				b.WriteString("<w:i/>")
+			}
+			b.WriteString("</w:rPr>")
+		}
+		// Line breaks inside a field become <w:br/> between text elements
+		for i, line := range strings.Split(strings.ReplaceAll(r.text, "\r", ""), "\n") {
+			if i > 0 {
+				b.WriteString("<w:br/>")
+			}
+			b.WriteString(`<w:t xml:space="preserve">`)
+			xml.EscapeText(b, []byte(line))
+			b.WriteString("</w:t>")
+		}
+		b.WriteString("</w:r>")
+	}
+	b.WriteString("</w:p>")
+}
+
+// docxCoreProps returns the document properties, titled with the candidate's name
+func docxCoreProps(name string) string {
+	var title bytes.Buffer
+	xml.EscapeText(&title, []byte(joinNonEmpty(" - ", name, "Resume")))
+	return xml.Header + `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/">` +
+		`<dc:title>` + title.String() + `</dc:title>` +
+		`</cp:coreProperties>`
+}
+
+const docxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+<Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>
+<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>
+</Types>`
+
+const docxRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>
+</Relationships>`
+
+const docxDocumentRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering" Target="numbering.xml"/>
+</Relationships>`
+
+const docxDocumentStart = xml.Header + `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`
+
+// The page is A4 with 2 cm margins, measured in twentieths of a point
+const docxDocumentEnd = `<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1134" w:right="1134" w:bottom="1134" w:left="1134" w:header="708" w:footer="708" w:gutter="0"/></w:sectPr></w:body></w:document>`
+
+const docxStyles = xml.Header + `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:docDefaults>
+<w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri" w:eastAsia="Calibri" w:cs="Calibri"/><w:sz w:val="22"/><w:szCs w:val="22"/></w:rPr></w:rPrDefault>
+<w:pPrDefault><w:pPr><w:spacing w:after="60" w:line="264" w:lineRule="auto"/></w:pPr></w:pPrDefault>
+</w:docDefaults>
+<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>
+<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>
+<w:pPr><w:spacing w:after="120"/></w:pPr><w:rPr><w:b/><w:sz w:val="36"/><w:szCs w:val="36"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>
+<w:pPr><w:keepNext/><w:pBdr><w:bottom w:val="single" w:sz="4" w:space="1" w:color="auto"/></w:pBdr><w:spacing w:before="240" w:after="80"/><w:outlineLvl w:val="0"/></w:pPr>
+<w:rPr><w:b/><w:caps/><w:sz w:val="26"/><w:szCs w:val="26"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>
+<w:pPr><w:keepNext/><w:spacing w:before="120" w:after="20"/><w:outlineLvl w:val="1"/></w:pPr>
+<w:rPr><w:b/><w:sz w:val="22"/><w:szCs w:val="22"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="ListBullet"><w:name w:val="List Bullet"/><w:basedOn w:val="Normal"/><w:qFormat/>
+<w:pPr><w:numPr><w:numId w:val="1"/></w:numPr><w:spacing w:after="20"/><w:ind w:left="360" w:hanging="360"/></w:pPr></w:style>
+</w:styles>`
+
+const docxNumbering = xml.Header + `<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:abstractNum w:abstractNumId="0"><w:multiLevelType w:val="singleLevel"/>
+<w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="bullet"/><w:lvlText w:val="•"/><w:lvlJc w:val="left"/><w:pPr><w:ind w:left="360" w:hanging="360"/></w:pPr></w:lvl>
+</w:abstractNum>
+<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>
+</w:numbering>`
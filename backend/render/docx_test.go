@@ -0,0 +1,159 @@
+package render
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"resume.in/backend/models"
+)
+
+// docxDocument is the part of word/document.xml the tests look at
+type docxDocument struct {
+	Paragraphs []struct {
+		Style struct {
+			Val string `xml:"val,attr"`
+		} `xml:"pPr>pStyle"`
+		Runs []struct {
+			Texts []string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"body>p"`
+}
+
+// readDOCX unzips a document and returns its parts by name
+func readDOCX(t *testing.T, data []byte) map[string]string {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("DOCX() output is not a zip: %v", err)
+	}
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name] = string(content)
+	}
+	return parts
+}
+
+// docxParagraphs lists the paragraphs of word/document.xml as "Style: text",
+// with line breaks inside a paragraph shown as "|"
+func docxParagraphs(t *testing.T, document string) []string {
+	var doc docxDocument
+	if err := xml.Unmarshal([]byte(document), &doc); err != nil {
+		t.Fatalf("word/document.xml is not valid XML: %v", err)
+	}
+	var paragraphs []string
+	for _, p := range doc.Paragraphs {
+		var text []string
+		for _, r := range p.Runs {
+			text = append(text, strings.Join(r.Texts, "|"))
+		}
+		paragraphs = append(paragraphs, p.Style.Val+": "+strings.Join(text, ""))
+	}
+	return paragraphs
+}
+
+func TestDOCX(t *testing.T) {
+	tests := []struct {
+		name   string
+		resume models.Resume
+		want   []string
+	}{
+		{
+			name: "sections follow the classic order",
+			resume: models.Resume{
+				BasicInfo: models.BasicInfo{Name: "Ada Lovelace", Email: "ada@example.com"},
+				Summary:   "Mathematician.",
+				Projects:  []models.Project{{Name: "Engine notes"}},
+				Skills:    []models.Skill{{Name: "Go", Level: "Expert"}, {Name: "SQL"}},
+				Education: []models.Education{{Degree: "BSc", Field: "Mathematics", Institution: "London University"}},
+				Experience: []models.Experience{{
+					Position:    "Analyst",
+					Company:     "Analytical Engines",
+					StartDate:   "1842",
+					EndDate:     "1843",
+					Description: "Wrote the first program.",
+					Highlights:  []string{"Notes on the engine", "Bernoulli numbers"},
+				}},
+				Certificates: []models.Certificate{{Name: "Royal Society", Issuer: "London"}},
+			},
+			want: []string{
+				"Title: Ada Lovelace",
+				": Email: ada@example.com",
+				"Heading1: Professional Summary",
+				": Mathematician.",
+				"Heading1: Experience",
+				"Heading2: Analyst | Analytical Engines",
+				": 1842 - 1843",
+				": Wrote the first program.",
+				"ListBullet: Notes on the engine",
+				"ListBullet: Bernoulli numbers",
+				"Heading1: Education",
+				"Heading2: BSc in Mathematics",
+				": London University",
+				"Heading1: Skills",
+				"ListBullet: Go (Expert)",
+				"ListBullet: SQL",
+				"Heading1: Certifications",
+				"Heading2: Royal Society",
+				": London",
+				"Heading1: Projects",
+				"Heading2: Engine notes",
+			},
+		},
+		{
+			name:   "empty sections are left out",
+			resume: models.Resume{BasicInfo: models.BasicInfo{Name: "Ada Lovelace"}, Skills: []models.Skill{{Name: "Go"}}},
+			want:   []string{"Title: Ada Lovelace", "Heading1: Skills", "ListBullet: Go"},
+		},
+		{
+			name:   "text is escaped and line breaks are kept",
+			resume: models.Resume{BasicInfo: models.BasicInfo{Name: "Ada <Lovelace> & Co"}, Summary: "First line\r\nSecond line"},
+			want:   []string{"Title: Ada <Lovelace> & Co", "Heading1: Professional Summary", ": First line|Second line"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := DOCX(&buf, tt.resume); err != nil {
+				t.Fatalf("DOCX() error = %v", err)
+			}
+			parts := readDOCX(t, buf.Bytes())
+
+			// Every part is listed in the content types, and the main
+			// document is declared as a Word document
+			types, ok := parts["[Content_Types].xml"]
+			if !ok {
+				t.Fatal("DOCX() has no [Content_Types].xml")
+			}
+			if !strings.Contains(types, `<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>`) {
+				t.Errorf("[Content_Types].xml does not declare word/document.xml: %s", types)
+			}
+			for name := range parts {
+				if strings.HasSuffix(name, ".xml") && name != "[Content_Types].xml" && !strings.Contains(types, `"/`+name+`"`) {
+					t.Errorf("[Content_Types].xml does not declare %s", name)
+				}
+			}
+
+			document, ok := parts["word/document.xml"]
+			if !ok {
+				t.Fatal("DOCX() has no word/document.xml")
+			}
+			if got := docxParagraphs(t, document); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DOCX() paragraphs =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package render
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+// fontFiles holds the TrueType fonts embedded into every PDF. The licenses
+// are next to them in the fonts directory.
+//
+//go:embed fonts/*.ttf
+var fontFiles embed.FS
+
+// face is an embedded font family
+type face struct {
+	name  string            // Family name registered with gofpdf
+	files map[string]string // gofpdf style ("", "B", "I" or "BI") to file name
+
+	once sync.Once
+	cmap *sfnt.Font // Regular style, used to look up which characters the family has
+	err  error
+}
+
+// Font families selectable through Options.Font
+var (
+	liberationSans = &face{name: "Liberation Sans", files: map[string]string{
+		"": "LiberationSans-Regular.ttf", "B": "LiberationSans-Bold.ttf",
+		"I": "LiberationSans-Italic.ttf", "BI": "LiberationSans-BoldItalic.ttf",
+	}}
+	liberationSerif = &face{name: "Liberation Serif", files: map[string]string{
+		"": "LiberationSerif-Regular.ttf", "B": "LiberationSerif-Bold.ttf",
+		"I": "LiberationSerif-Italic.ttf", "BI": "LiberationSerif-BoldItalic.ttf",
+	}}
+	liberationMono = &face{name: "Liberation Mono", files: map[string]string{
+		"": "LiberationMono-Regular.ttf", "B": "LiberationMono-Bold.ttf",
+		"I": "LiberationMono-Italic.ttf", "BI": "LiberationMono-BoldItalic.ttf",
+	}}
+)
+
+// fallbackFaces are tried in order for text the selected family cannot show.
+// DejaVu Sans covers most alphabetic scripts beyond Latin, Greek and Cyrillic
+// (Armenian, Georgian, Hebrew and more). M+ 1p covers Japanese kana and the
+// kanji in Japanese use, but not most Simplified Chinese characters, which
+// WenQuanYi Micro Hei has along with the rest of the CJK ideographs and Hangul.
+var fallbackFaces = []*face{
+	{name: "DejaVu Sans", files: map[string]string{"": "DejaVuSans.ttf", "B": "DejaVuSans-Bold.ttf"}},
+	{name: "M+ 1p", files: map[string]string{"": "MPlus1p-Regular.ttf"}},
+	{name: "WenQuanYi Micro Hei", files: map[string]string{"": "WenQuanYiMicroHei-Regular.ttf"}},
+}
+
+// faces maps the names accepted in Options.Font to font families. The core PDF
+// font names are kept as aliases for their metric-compatible Liberation fonts.
+var faces = map[string]*face{
+	"liberation sans":  liberationSans,
+	"liberation serif": liberationSerif,
+	"liberation mono":  liberationMono,
+	"helvetica":        liberationSans,
+	"arial":            liberationSans,
+	"times":            liberationSerif,
+	"times new roman":  liberationSerif,
+	"courier":          liberationMono,
+	"courier new":      liberationMono,
+}
+
+// fontData caches the bytes of embedded font files
+var fontData sync.Map
+
+// readFont returns the contents of an embedded font file
+func readFont(file string) ([]byte, error) {
+	if data, ok := fontData.Load(file); ok {
+		return data.([]byte), nil
+	}
+	data, err := fontFiles.ReadFile("fonts/" + file)
+	if err != nil {
+		return nil, err
+	}
+	fontData.Store(file, data)
+	return data, nil
+}
+
+// file returns the file for a style, substituting the closest style the family has
+func (f *face) file(style string) (string, string) {
+	for _, candidate := range []string{style, strings.Replace(style, "I", "", 1), ""} {
+		if file, ok := f.files[candidate]; ok {
+			return candidate, file
+		}
+	}
+	return "", f.files[""]
+}
+
+// covers reports whether the family has a glyph for r
+func (f *face) covers(r rune) bool {
+	f.once.Do(func() {
+		data, err := readFont(f.files[""])
+		if err != nil {
+			f.err = err
+			return
+		}
+		f.cmap, f.err = sfnt.Parse(data)
+	})
+	if f.err != nil {
+		return false
+	}
+
+	index, err := f.cmap.GlyphIndex(nil, r)
+	return err == nil && index != 0
+}
+
+// pickFace returns the first of primary and the fallback families that can
+// show every character of txt. When none can, it returns the one that shows
+// the most, so a single unsupported character does not change the font.
+func pickFace(primary *face, txt string) *face {
+	return bestFace(append([]*face{primary}, fallbackFaces...), txt)
+}
+
+// bestFace returns the first of candidates that can show every character of
+// txt, or else the one that shows the most
+func bestFace(candidates []*face, txt string) *face {
+	best, bestCovered := candidates[0], -1
+	for _, candidate := range candidates {
+		covered, total := 0, 0
+		for _, r := range txt {
+			if unicode.IsSpace(r) || unicode.IsControl(r) {
+				continue
+			}
+			total++
+			if candidate.covers(r) {
+				covered++
+			}
+		}
+		if covered == total {
+			return candidate
+		}
+		if covered > bestCovered {
+			best, bestCovered = candidate, covered
+		}
+	}
+	return best
+}
+
+// run is a stretch of text shown in one font family
+type run struct {
+	face *face
+	text string
+}
+
+// splitRuns splits txt into runs of the characters primary can show and runs
+// of those it cannot. Each of the latter gets the fallback family that shows
+// all of it, or the most of it, so a Chinese name is not split between a
+// Japanese and a Chinese font. Spaces stay in the current run.
+func splitRuns(primary *face, txt string) []run {
+	var runs []run
+	for _, r := range txt {
+		f := primary
+		if len(runs) > 0 && (unicode.IsSpace(r) || unicode.IsControl(r)) {
+			f = runs[len(runs)-1].face
+		} else if !primary.covers(r) {
+			f = nil // Chosen below, once the whole run is known
+		}
+
+		if len(runs) > 0 && runs[len(runs)-1].face == f {
+			runs[len(runs)-1].text += string(r)
+		} else {
+			runs = append(runs, run{face: f, text: string(r)})
+		}
+	}
+
+	merged := runs[:0]
+	for _, r := range runs {
+		if r.face == nil {
+			r.face = bestFace(fallbackFaces, r.text)
+		}
+		if len(merged) > 0 && merged[len(merged)-1].face == r.face {
+			merged[len(merged)-1].text += r.text
+		} else {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
+
+// replacementChar stands in for characters cleanText cannot keep
+const replacementChar = '\uFFFD'
+
+// cleanText replaces characters outside the Basic Multilingual Plane, such as
+// emoji and rare CJK ideographs, which gofpdf's UTF-8 font support cannot
+// measure. They show as the replacement character so the gap is visible.
+func cleanText(txt string) string {
+	return strings.Map(func(r rune) rune {
+		if r > 0xFFFF {
+			return replacementChar
+		}
+		return r
+	}, txt)
+}
+
+// fontKey identifies a family and style registered with a document
+func fontKey(f *face, style string) string {
+	return fmt.Sprintf("%s/%s", f.name, style)
+}
@@ -0,0 +1,151 @@
+package render
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+
+	"resume.in/backend/models"
+)
+
+// faceNamed returns the fallback family with the given name
+func faceNamed(t *testing.T, name string) *face {
+	for _, f := range fallbackFaces {
+		if f.name == name {
+			return f
+		}
+	}
+	t.Fatalf("no fallback family %q", name)
+	return nil
+}
+
+func TestSplitRuns(t *testing.T) {
+	dejavu, mplus, wqy := faceNamed(t, "DejaVu Sans"), faceNamed(t, "M+ 1p"), faceNamed(t, "WenQuanYi Micro Hei")
+
+	tests := []struct {
+		name string
+		txt  string
+		want []run
+	}{
+		{"Latin", "Ada Lovelace", []run{{liberationSans, "Ada Lovelace"}}},
+		{"Cyrillic is in the primary family", "Иван Петров", []run{{liberationSans, "Иван Петров"}}},
+		{"Armenian", "Anna Հայերեն", []run{{liberationSans, "Anna "}, {dejavu, "Հայերեն"}}},
+		{"Japanese", "山田 はなこ", []run{{mplus, "山田 はなこ"}}},
+		// 张 and 伟 are Simplified Chinese characters M+ 1p does not have
+		{"Simplified Chinese", "张伟 Zhang Wei", []run{{wqy, "张伟 "}, {liberationSans, "Zhang Wei"}}},
+		{"Chinese M+ 1p partly covers stays in one family", "经验 开发", []run{{wqy, "经验 开发"}}},
+		{"Korean", "김민수", []run{{wqy, "김민수"}}},
+		{"mixed Latin, Cyrillic and CJK", "Engineer Инженер at 华为, 腾讯", []run{
+			{liberationSans, "Engineer Инженер at "}, {wqy, "华为"}, {liberationSans, ", "}, {wqy, "腾讯"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitRuns(liberationSans, tt.txt); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitRuns(%q) = %s, want %s", tt.txt, runNames(got), runNames(tt.want))
+			}
+		})
+	}
+}
+
+// runNames describes runs by family name for test failures
+func runNames(runs []run) []string {
+	var names []string
+	for _, r := range runs {
+		names = append(names, r.face.name+": "+r.text)
+	}
+	return names
+}
+
+func TestPickFace(t *testing.T) {
+	tests := []struct {
+		txt  string
+		want string
+	}{
+		{"Ada Lovelace", "Liberation Sans"},
+		{"Иван Петров", "Liberation Sans"},
+		{"Anna Հայերեն", "DejaVu Sans"},
+		{"山田 はなこ", "M+ 1p"},
+		{"张伟 Zhang Wei", "WenQuanYi Micro Hei"},
+		{"陈杨 Chen Yang 刘", "WenQuanYi Micro Hei"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.txt, func(t *testing.T) {
+			if got := pickFace(liberationSans, tt.txt); got.name != tt.want {
+				t.Errorf("pickFace(%q) = %s, want %s", tt.txt, got.name, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanText(t *testing.T) {
+	tests := []struct {
+		txt  string
+		want string
+	}{
+		{"Ada Lovelace", "Ada Lovelace"},
+		{"张伟 Иван", "张伟 Иван"},
+		{"Shipped it 🚀", "Shipped it �"},
+		{"𠀀 and 😀😀", "� and ��"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.txt, func(t *testing.T) {
+			if got := cleanText(tt.txt); got != tt.want {
+				t.Errorf("cleanText(%q) = %q, want %q", tt.txt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPDFEmbedsFallbackFonts(t *testing.T) {
+	baseFont := regexp.MustCompile(`/BaseFont /utf8([^\s/]+)`)
+
+	tests := []struct {
+		name   string
+		resume models.Resume
+		want   []string // Embedded families, as gofpdf names them
+	}{
+		{
+			name:   "Latin and Cyrillic need no fallback",
+			resume: models.Resume{BasicInfo: models.BasicInfo{Name: "Ivan Petrov"}, Summary: "Инженер-программист"},
+			want:   []string{"liberation#20sans", "liberation#20sansB"},
+		},
+		{
+			name:   "Chinese uses WenQuanYi Micro Hei",
+			resume: models.Resume{BasicInfo: models.BasicInfo{Name: "张伟"}, Summary: "Software engineer, 5 years at 华为 and 腾讯"},
+			want:   []string{"liberation#20sans", "liberation#20sansB", "wenquanyi#20micro#20hei"},
+		},
+		{
+			name:   "Japanese uses M+ 1p",
+			resume: models.Resume{BasicInfo: models.BasicInfo{Name: "山田 はなこ"}, Summary: "Engineer"},
+			want:   []string{"liberation#20sans", "liberation#20sansB", "m+#201p"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := PDF(&buf, tt.resume, "", Options{}); err != nil {
+				t.Fatalf("PDF() error = %v", err)
+			}
+
+			seen := map[string]bool{}
+			for _, m := range baseFont.FindAllSubmatch(buf.Bytes(), -1) {
+				seen[string(m[1])] = true
+			}
+			var got []string
+			for name := range seen {
+				got = append(got, name)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PDF() embeds %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"resume.in/backend/models"
+)
+
+// ErrUnknownFormat is returned when a resume cannot be exported in the requested format
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// DefaultFormat is used when the caller does not pick a format
+const DefaultFormat = "pdf"
+
+// Format is a file type resumes can be exported as
+type Format struct {
+	Name        string `json:"name"`        // Value of the format selector, also the file extension
+	ContentType string `json:"contentType"` // MIME type of the exported file
+	// Templated formats are laid out with the template and options from the
+	// request. The others ignore them.
+	Templated bool `json:"templated"`
+	// Custom formats are rendered from a text template, which users can replace
+	// with one they uploaded
+	Custom bool `json:"custom"`
+
+	html  bool // Custom templates are parsed with html/template
+	write func(w io.Writer, resume models.Resume, templateName string, opts Options) error
+}
+
+// formats holds the supported export formats
+var formats = []Format{
+	{Name: "pdf", ContentType: "application/pdf", Templated: true, write: PDF},
+	{
+		Name:        "docx",
+		ContentType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		write: func(w io.Writer, resume models.Resume, _ string, _ Options) error {
+			return DOCX(w, resume)
+		},
+	},
+	textFormat("md", "text/markdown; charset=utf-8", false),
+	textFormat("html", "text/html; charset=utf-8", true),
+	textFormat("txt", "text/plain; charset=utf-8", false),
+}
+
+// Formats returns the supported export formats
+func Formats() []Format {
+	return append([]Format(nil), formats...)
+}
+
+// LookupFormat returns the format with the given name. An empty name selects DefaultFormat.
+func LookupFormat(name string) (Format, error) {
+	if name == "" {
+		name = DefaultFormat
+	}
+	for _, f := range formats {
+		if strings.EqualFold(f.Name, name) {
+			return f, nil
+		}
+	}
+	names := make([]string, 0, len(formats))
+	for _, f := range formats {
+		names = append(names, f.Name)
+	}
+	return Format{}, fmt.Errorf("%w %q: format must be one of %s", ErrUnknownFormat, name, strings.Join(names, ", "))
+}
+
+// Write exports the resume in this format to w
+func (f Format) Write(w io.Writer, resume models.Resume, templateName string, opts Options) error {
+	return f.write(w, resume, templateName, opts)
+}
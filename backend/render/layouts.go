@@ -0,0 +1,316 @@
+package render
+
+import (
+	"strings"
+
+	"resume.in/backend/models"
+)
+
+func init() {
+	register(Template{
+		Name:        "classic",
+		Title:       "Classic ATS",
+		Description: "Single column with plain section headings, the safest choice for applicant tracking systems",
+		Defaults:    Options{Paper: "A4", Font: "Liberation Sans", Accent: "#000000"},
+		render:      renderClassic,
+	})
+	register(Template{
+		Name:        "modern",
+		Title:       "Modern two-column",
+		Description: "Contact details, skills and education in a sidebar next to experience and projects",
+		Defaults:    Options{Paper: "A4", Font: "Liberation Sans", Accent: "#1F6FB2"},
+		render:      renderModern,
+	})
+	register(Template{
+		Name:        "compact",
+		Title:       "Compact one-page",
+		Description: "Dense single column that shrinks its text to fit everything on one page",
+		Defaults:    Options{Paper: "A4", Font: "Liberation Sans", Accent: "#333333"},
+		OnePage:     true,
+		render:      renderCompact,
+	})
+	register(Template{
+		Name:        "academic",
+		Title:       "Academic CV",
+		Description: "Centred header with education first, followed by appointments, research and honours",
+		Defaults:    Options{Paper: "A4", Font: "Liberation Serif", Accent: "#7A1F1F"},
+		render:      renderAcademic,
+	})
+}
+
+// renderClassic is the original ATS layout
+func renderClassic(d *doc, r models.Resume) {
+	d.text("B", 16, r.BasicInfo.Name)
+	d.gap(2)
+
+	info := r.BasicInfo
+	for _, line := range []struct{ label, value string }{
+		{"Email", info.Email},
+		{"Phone", info.Phone},
+		{"Address", info.Address},
+		{"Website", info.Website},
+		{"LinkedIn", info.LinkedIn},
+		{"GitHub", info.GitHub},
+	} {
+		if line.value != "" {
+			d.text("", 10, line.label+": "+line.value)
+		}
+	}
+
+	if r.Summary != "" {
+		d.heading("PROFESSIONAL SUMMARY", 12, false)
+		d.text("", 10, r.Summary)
+	}
+	if len(r.Experience) > 0 {
+		d.heading("EXPERIENCE", 12, false)
+		writeExperience(d, 10, r.Experience)
+	}
+	if len(r.Education) > 0 {
+		d.heading("EDUCATION", 12, false)
+		writeEducation(d, 10, r.Education)
+	}
+	if len(r.Skills) > 0 {
+		d.heading("SKILLS", 12, false)
+		d.text("", 10, skillList(r.Skills, " • "))
+	}
+	if len(r.Certificates) > 0 {
+		d.heading("CERTIFICATIONS", 12, false)
+		writeCertificates(d, 10, r.Certificates)
+	}
+	if len(r.Projects) > 0 {
+		d.heading("PROJECTS", 12, false)
+		writeProjects(d, 10, r.Projects)
+	}
+}
+
+// renderModern puts a sidebar next to the main column
+func renderModern(d *doc, r models.Resume) {
+	pdf := d.pdf
+	pageWidth, _ := pdf.GetPageSize()
+	left, top, right, _ := pdf.GetMargins()
+	sidebarWidth := d.width() * 0.32
+	mainLeft := left + sidebarWidth + 6
+
+	// The name spans both columns
+	d.accent()
+	d.text("B", 22, r.BasicInfo.Name)
+	y := pdf.GetY() + 1
+	pdf.SetLineWidth(0.6)
+	pdf.Line(left, y, pageWidth-right, y)
+	pdf.SetLineWidth(0.2)
+	d.plain()
+	y += 3
+	firstPage := pdf.PageNo()
+
+	// Main column first, so it can run over as many pages as it needs
+	pdf.SetLeftMargin(mainLeft)
+	pdf.SetXY(mainLeft, y)
+	if r.Summary != "" {
+		d.heading("Profile", 12, true)
+		d.text("", 10, r.Summary)
+	}
+	if len(r.Experience) > 0 {
+		d.heading("Experience", 12, true)
+		writeExperience(d, 10, r.Experience)
+	}
+	if len(r.Projects) > 0 {
+		d.heading("Projects", 12, true)
+		writeProjects(d, 10, r.Projects)
+	}
+
+	// The sidebar continues on the pages the main column created before adding new ones
+	pdf.SetPage(firstPage)
+	pdf.SetLeftMargin(left)
+	pdf.SetRightMargin(pageWidth - left - sidebarWidth)
+	pdf.SetXY(left, y)
+	pdf.SetAcceptPageBreakFunc(func() bool {
+		if pdf.PageNo() < pdf.PageCount() {
+			pdf.SetPage(pdf.PageNo() + 1)
+			pdf.SetY(top)
+			return false
+		}
+		return true
+	})
+
+	info := r.BasicInfo
+	if contact := nonEmpty(info.Email, info.Phone, info.Address, info.Website, info.LinkedIn, info.GitHub); len(contact) > 0 {
+		d.heading("Contact", 11, true)
+		for _, line := range contact {
+			d.text("", 9, line)
+		}
+	}
+	if len(r.Skills) > 0 {
+		d.heading("Skills", 11, true)
+		for _, skill := range r.Skills {
+			d.text("", 9, skillLabel(skill))
+		}
+	}
+	if len(r.Education) > 0 {
+		d.heading("Education", 11, true)
+		for _, edu := range r.Education {
+			d.text("B", 9, joinNonEmpty(" in ", edu.Degree, edu.Field))
+			d.text("", 9, edu.Institution)
+			d.text("I", 9, joinNonEmpty(" - ", edu.StartDate, edu.EndDate))
+			if edu.GPA != "" {
+				d.text("", 9, "GPA: "+edu.GPA)
+			}
+			d.gap(2)
+		}
+	}
+	if len(r.Certificates) > 0 {
+		d.heading("Certifications", 11, true)
+		for _, cert := range r.Certificates {
+			d.text("B", 9, cert.Name)
+			d.text("", 9, joinNonEmpty(", ", cert.Issuer, cert.IssueDate))
+			d.gap(2)
+		}
+	}
+
+	pdf.SetAcceptPageBreakFunc(func() bool { return true })
+	pdf.SetPage(pdf.PageCount())
+	pdf.SetMargins(left, top, right)
+}
+
+// renderCompact is a dense single column. Template.build shrinks it to one page.
+func renderCompact(d *doc, r models.Resume) {
+	d.accent()
+	d.text("B", 14, r.BasicInfo.Name)
+	d.plain()
+	info := r.BasicInfo
+	d.text("", 8.5, strings.Join(nonEmpty(info.Email, info.Phone, info.Address, info.Website, info.LinkedIn, info.GitHub), " | "))
+
+	if r.Summary != "" {
+		d.heading("SUMMARY", 10, true)
+		d.text("", 8.5, r.Summary)
+	}
+	if len(r.Experience) > 0 {
+		d.heading("EXPERIENCE", 10, true)
+		writeExperience(d, 8.5, r.Experience)
+	}
+	if len(r.Projects) > 0 {
+		d.heading("PROJECTS", 10, true)
+		writeProjects(d, 8.5, r.Projects)
+	}
+	if len(r.Education) > 0 {
+		d.heading("EDUCATION", 10, true)
+		writeEducation(d, 8.5, r.Education)
+	}
+	if len(r.Skills) > 0 {
+		d.heading("SKILLS", 10, true)
+		d.text("", 8.5, skillList(r.Skills, ", "))
+	}
+	if len(r.Certificates) > 0 {
+		d.heading("CERTIFICATIONS", 10, true)
+		for _, cert := range r.Certificates {
+			d.text("", 8.5, joinNonEmpty(", ", cert.Name, cert.Issuer, cert.IssueDate))
+		}
+	}
+}
+
+// renderAcademic follows the usual order of an academic CV
+func renderAcademic(d *doc, r models.Resume) {
+	d.align("B", 18, r.BasicInfo.Name, "C")
+	info := r.BasicInfo
+	d.align("", 10, strings.Join(nonEmpty(info.Address, info.Email, info.Phone), " · "), "C")
+	d.align("", 10, strings.Join(nonEmpty(info.Website, info.LinkedIn, info.GitHub), " · "), "C")
+
+	if r.Summary != "" {
+		d.heading("Research Interests", 13, true)
+		d.text("", 11, r.Summary)
+	}
+	if len(r.Education) > 0 {
+		d.heading("Education", 13, true)
+		writeEducation(d, 11, r.Education)
+	}
+	if len(r.Experience) > 0 {
+		d.heading("Appointments", 13, true)
+		writeExperience(d, 11, r.Experience)
+	}
+	if len(r.Projects) > 0 {
+		d.heading("Research and Projects", 13, true)
+		writeProjects(d, 11, r.Projects)
+	}
+	if len(r.Certificates) > 0 {
+		d.heading("Honours and Certifications", 13, true)
+		writeCertificates(d, 11, r.Certificates)
+	}
+	if len(r.Skills) > 0 {
+		d.heading("Skills", 13, true)
+		d.text("", 11, skillList(r.Skills, "; "))
+	}
+}
+
+// writeExperience writes work experience entries
+func writeExperience(d *doc, size float64, experience []models.Experience) {
+	for _, exp := range experience {
+		d.row("B", size, joinNonEmpty(" | ", exp.Position, exp.Company), joinNonEmpty(" - ", exp.StartDate, exp.EndDate))
+		d.text("", size, exp.Description)
+		for _, highlight := range exp.Highlights {
+			d.bullet(size, highlight)
+		}
+		d.gap(2)
+	}
+}
+
+// writeEducation writes education entries
+func writeEducation(d *doc, size float64, education []models.Education) {
+	for _, edu := range education {
+		d.row("B", size, joinNonEmpty(" in ", edu.Degree, edu.Field), joinNonEmpty(" - ", edu.StartDate, edu.EndDate))
+		gpa := ""
+		if edu.GPA != "" {
+			gpa = "GPA: " + edu.GPA
+		}
+		d.text("", size, joinNonEmpty(", ", edu.Institution, gpa))
+		d.gap(2)
+	}
+}
+
+// writeCertificates writes certificate entries
+func writeCertificates(d *doc, size float64, certificates []models.Certificate) {
+	for _, cert := range certificates {
+		d.row("B", size, cert.Name, cert.IssueDate)
+		d.text("", size, joinNonEmpty(" | ", cert.Issuer, cert.URL))
+		d.gap(1)
+	}
+}
+
+// writeProjects writes project entries
+func writeProjects(d *doc, size float64, projects []models.Project) {
+	for _, project := range projects {
+		d.row("B", size, project.Name, joinNonEmpty(" - ", project.StartDate, project.EndDate))
+		d.text("", size, project.Description)
+		if len(project.Technologies) > 0 {
+			d.text("I", size, "Technologies: "+strings.Join(project.Technologies, ", "))
+		}
+		d.text("", size, project.URL)
+		d.gap(2)
+	}
+}
+
+// skillLabel formats a skill with its level, if any
+func skillLabel(skill models.Skill) string {
+	if skill.Level == "" {
+		return skill.Name
+	}
+	return skill.Name + " (" + skill.Level + ")"
+}
+
+// skillList joins skill labels with sep
+func skillList(skills []models.Skill, sep string) string {
+	labels := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		labels = append(labels, skillLabel(skill))
+	}
+	return strings.Join(labels, sep)
+}
+
+// nonEmpty returns the non-empty values
+func nonEmpty(values ...string) []string {
+	var kept []string
+	for _, value := range values {
+		if value != "" {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,231 @@
+package render
+
+import (
+	"io"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"resume.in/backend/models"
+)
+
+// Filename returns the download filename for a resume with the given extension
+func Filename(resume models.Resume, ext string) string {
+	filename := "ATS_Resume." + ext
+	if resume.BasicInfo.Name != "" {
+		filename = "ATS_Resume_" + resume.BasicInfo.Name + "." + ext
+	}
+	return strings.ReplaceAll(filename, " ", "_")
+}
+
+// PDF renders the resume with the named template and writes it to w
+func PDF(w io.Writer, resume models.Resume, templateName string, opts Options) error {
+	template, err := Lookup(templateName)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := template.resolve(opts)
+	if err != nil {
+		return err
+	}
+
+	return template.build(resume, resolved).Output(w)
+}
+
+// build lays out the resume, shrinking one-page templates until they fit
+func (t Template) build(resume models.Resume, opts resolvedOptions) *gofpdf.Fpdf {
+	d := newDoc(opts, 1)
+	t.render(d, resume)
+
+	if t.OnePage {
+		for scale := 0.95; d.pdf.PageCount() > 1 && scale >= 0.6; scale -= 0.05 {
+			d = newDoc(opts, scale)
+			t.render(d, resume)
+		}
+	}
+
+	return d.pdf
+}
+
+// margin is the page margin in millimetres
+const margin = 12.0
+
+// doc wraps a PDF with the text helpers the templates share
+type doc struct {
+	pdf    *gofpdf.Fpdf
+	opts   resolvedOptions
+	scale  float64         // Multiplies every font size and vertical gap
+	loaded map[string]bool // Font families and styles added to the PDF so far
+}
+
+// newDoc starts a PDF on the first page
+func newDoc(opts resolvedOptions, scale float64) *doc {
+	pdf := gofpdf.New("P", "mm", opts.Paper, "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.AddPage()
+
+	return &doc{
+		pdf:    pdf,
+		opts:   opts,
+		scale:  scale,
+		loaded: map[string]bool{},
+	}
+}
+
+// use sets the font for writing txt with the given style and size in points.
+// It uses the family from the options unless that family is missing some of
+// the characters in txt, in which case it picks a fallback family that has them.
+// Fonts are embedded into the PDF the first time they are used.
+func (d *doc) use(style string, size float64, txt string) {
+	d.setFace(pickFace(d.opts.Face, txt), style, size)
+}
+
+// setFace sets the font to the given family, style and size in points
+func (d *doc) setFace(f *face, style string, size float64) {
+	style, file := f.file(style)
+
+	if key := fontKey(f, style); !d.loaded[key] {
+		data, err := readFont(file)
+		if err != nil {
+			d.pdf.SetError(err)
+			return
+		}
+		d.pdf.AddUTF8FontFromBytes(f.name, style, data)
+		d.loaded[key] = true
+	}
+
+	d.pdf.SetFont(f.name, style, size*d.scale)
+}
+
+// lineHeight is the height in millimetres of a line of text of the given size
+func (d *doc) lineHeight(size float64) float64 {
+	return size * d.scale * 0.5
+}
+
+// accent switches text and lines to the accent colour
+func (d *doc) accent() {
+	d.pdf.SetTextColor(d.opts.Accent[0], d.opts.Accent[1], d.opts.Accent[2])
+	d.pdf.SetDrawColor(d.opts.Accent[0], d.opts.Accent[1], d.opts.Accent[2])
+}
+
+// plain switches text and lines back to black
+func (d *doc) plain() {
+	d.pdf.SetTextColor(0, 0, 0)
+	d.pdf.SetDrawColor(0, 0, 0)
+}
+
+// width is the width between the current margins
+func (d *doc) width() float64 {
+	pageWidth, _ := d.pdf.GetPageSize()
+	left, _, right, _ := d.pdf.GetMargins()
+	return pageWidth - left - right
+}
+
+// gap adds vertical space
+func (d *doc) gap(mm float64) {
+	d.pdf.Ln(mm * d.scale)
+}
+
+// text writes a wrapped paragraph. Empty text writes nothing.
+func (d *doc) text(style string, size float64, txt string) {
+	d.align(style, size, txt, "L")
+}
+
+// align writes a wrapped paragraph with the given alignment (L, C or R)
+func (d *doc) align(style string, size float64, txt, alignment string) {
+	txt = cleanText(txt)
+	if txt == "" {
+		return
+	}
+
+	h := d.lineHeight(size)
+	runs := splitRuns(d.opts.Face, txt)
+	if len(runs) == 1 || alignment != "L" {
+		d.use(style, size, txt)
+		d.pdf.MultiCell(0, h, txt, "", alignment, false)
+		return
+	}
+
+	// Text mixing scripts is written a run at a time, so each script gets a
+	// font that has it. Wrapped lines line up with where the paragraph started.
+	leftMargin, _, _, _ := d.pdf.GetMargins()
+	d.pdf.SetLeftMargin(d.pdf.GetX())
+	for _, r := range runs {
+		d.setFace(r.face, style, size)
+		d.pdf.Write(h, r.text)
+	}
+	d.pdf.Ln(h)
+	d.pdf.SetLeftMargin(leftMargin)
+}
+
+// heading writes a section heading in the accent colour, underlined when rule is set
+func (d *doc) heading(txt string, size float64, rule bool) {
+	d.gap(3)
+	d.accent()
+	d.text("B", size, txt)
+	if rule {
+		left, _, right, _ := d.pdf.GetMargins()
+		pageWidth, _ := d.pdf.GetPageSize()
+		y := d.pdf.GetY() + 0.5
+		d.pdf.Line(left, y, pageWidth-right, y)
+		d.gap(1.5)
+	}
+	d.plain()
+	d.gap(1)
+}
+
+// row writes left-aligned text with right-aligned text, such as dates, on the same line
+func (d *doc) row(style string, size float64, left, right string) {
+	left, right = cleanText(left), cleanText(right)
+	if left == "" && right == "" {
+		return
+	}
+
+	h := d.lineHeight(size)
+	width := d.width()
+
+	rightWidth := 0.0
+	if right != "" {
+		d.use("I", size, right)
+		rightWidth = d.pdf.GetStringWidth(right) + 2
+	}
+
+	d.use(style, size, left)
+	lines := d.pdf.SplitText(left, width-rightWidth)
+	first := ""
+	if len(lines) > 0 {
+		first = lines[0]
+	}
+
+	leftMargin, _, _, _ := d.pdf.GetMargins()
+	d.pdf.SetX(leftMargin)
+	d.pdf.CellFormat(width-rightWidth, h, first, "", 0, "L", false, 0, "")
+
+	d.use("I", size, right)
+	d.pdf.CellFormat(rightWidth, h, right, "", 1, "R", false, 0, "")
+
+	if len(lines) > 1 {
+		d.use(style, size, left)
+		d.pdf.MultiCell(width-rightWidth, h, strings.Join(lines[1:], " "), "", "L", false)
+	}
+}
+
+// bullet writes an indented bullet point
+func (d *doc) bullet(size float64, txt string) {
+	txt = cleanText(txt)
+	if txt == "" {
+		return
+	}
+	h := d.lineHeight(size)
+	left := d.pdf.GetX()
+	d.use("", size, "•")
+	d.pdf.CellFormat(4*d.scale, h, "•", "", 0, "L", false, 0, "")
+	d.align("", size, txt, "L")
+	d.pdf.SetX(left)
+}
+
+// joinNonEmpty joins the non-empty parts with sep
+func joinNonEmpty(sep string, parts ...string) string {
+	return strings.Join(nonEmpty(parts...), sep)
+}
@@ -0,0 +1,143 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"resume.in/backend/models"
+)
+
+var (
+	// ErrUnknownTemplate is returned when no template is registered under a name
+	ErrUnknownTemplate = errors.New("unknown template")
+	// ErrInvalidOptions is returned when a paper size, font or colour is not supported
+	ErrInvalidOptions = errors.New("invalid template options")
+)
+
+// PaperSizes lists the supported paper sizes
+var PaperSizes = []string{"A4", "Letter"}
+
+// Fonts lists the embedded font families. Helvetica, Arial, Times, Times New
+// Roman, Courier and Courier New are accepted as aliases for their
+// metric-compatible Liberation family.
+var Fonts = []string{"Liberation Sans", "Liberation Serif", "Liberation Mono"}
+
+// DefaultTemplate is used when the caller does not pick a template
+const DefaultTemplate = "classic"
+
+// Options customise how a template renders. Empty fields use the template's defaults.
+type Options struct {
+	Paper  string `json:"paper"`  // A4 or Letter
+	Font   string `json:"font"`   // Font family, one of Fonts or an alias
+	Accent string `json:"accent"` // Accent colour as #RRGGBB
+}
+
+// Template is a named resume layout
+type Template struct {
+	Name        string  `json:"name"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Defaults    Options `json:"defaults"`
+	// OnePage templates shrink their text until the resume fits on one page
+	OnePage bool `json:"onePage"`
+
+	render func(d *doc, resume models.Resume)
+}
+
+// templates holds the registered templates in registration order
+var templates []Template
+
+// register adds a template to the registry
+func register(t Template) {
+	templates = append(templates, t)
+}
+
+// Templates returns all registered templates
+func Templates() []Template {
+	return append([]Template(nil), templates...)
+}
+
+// Lookup returns the template registered under name. An empty name selects DefaultTemplate.
+func Lookup(name string) (Template, error) {
+	if name == "" {
+		name = DefaultTemplate
+	}
+	for _, t := range templates {
+		if strings.EqualFold(t.Name, name) {
+			return t, nil
+		}
+	}
+	return Template{}, fmt.Errorf("%w %q", ErrUnknownTemplate, name)
+}
+
+// resolvedOptions are validated options ready for rendering
+type resolvedOptions struct {
+	Paper  string
+	Face   *face
+	Accent [3]int
+}
+
+// resolve fills empty options from the template defaults and validates them
+func (t Template) resolve(opts Options) (resolvedOptions, error) {
+	if opts.Paper == "" {
+		opts.Paper = t.Defaults.Paper
+	}
+	if opts.Font == "" {
+		opts.Font = t.Defaults.Font
+	}
+	if opts.Accent == "" {
+		opts.Accent = t.Defaults.Accent
+	}
+
+	var resolved resolvedOptions
+
+	paper, ok := matchName(PaperSizes, opts.Paper)
+	if !ok {
+		return resolved, fmt.Errorf("%w: paper must be one of %s", ErrInvalidOptions, strings.Join(PaperSizes, ", "))
+	}
+	resolved.Paper = paper
+
+	face, ok := faces[strings.ToLower(opts.Font)]
+	if !ok {
+		return resolved, fmt.Errorf("%w: font must be one of %s", ErrInvalidOptions, strings.Join(Fonts, ", "))
+	}
+	resolved.Face = face
+
+	accent, err := parseColor(opts.Accent)
+	if err != nil {
+		return resolved, err
+	}
+	resolved.Accent = accent
+
+	return resolved, nil
+}
+
+// matchName finds name in names, ignoring case
+func matchName(names []string, name string) (string, bool) {
+	for _, candidate := range names {
+		if strings.EqualFold(candidate, name) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// parseColor parses a #RRGGBB colour
+func parseColor(color string) ([3]int, error) {
+	hex := strings.TrimPrefix(color, "#")
+	if len(hex) != 6 {
+		return [3]int{}, fmt.Errorf("%w: accent must be a #RRGGBB colour", ErrInvalidOptions)
+	}
+
+	var rgb [3]int
+	for i := range rgb {
+		value, err := strconv.ParseUint(hex[i*2:i*2+2], 16, 8)
+		if err != nil {
+			return [3]int{}, fmt.Errorf("%w: accent must be a #RRGGBB colour", ErrInvalidOptions)
+		}
+		rgb[i] = int(value)
+	}
+	return rgb, nil
+}
@@ -0,0 +1,178 @@
+package render
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+
+	"resume.in/backend/models"
+)
+
+// templateFiles holds the built-in text export templates, named resume.<format>.tmpl
+//
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// ErrInvalidTemplate is returned when a custom template does not parse or fails to run
+var ErrInvalidTemplate = errors.New("invalid template")
+
+// MaxCustomTemplateSize is the largest custom template accepted, in bytes
+const MaxCustomTemplateSize = 64 << 10
+
+// MaxCustomOutputSize is the largest export a custom template may produce, in
+// bytes. Nested ranges can multiply a small template into a huge file.
+const MaxCustomOutputSize = 4 << 20
+
+// executor is a parsed text/template or html/template
+type executor interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// templateFuncs are available to every text export template, built-in or custom
+var templateFuncs = map[string]interface{}{
+	"join":         strings.Join,
+	"joinNonEmpty": joinNonEmpty,
+	"upper":        strings.ToUpper,
+	"dates": func(start, end string) string {
+		return joinNonEmpty(" - ", start, end)
+	},
+	"skill":  skillLabel,
+	"skills": skillList,
+	"contact": func(info models.BasicInfo) []string {
+		return nonEmpty(info.Email, info.Phone, info.Address, info.Website, info.LinkedIn, info.GitHub)
+	},
+}
+
+// parseTemplate parses source with html/template, which escapes the resume's
+// text, or with text/template
+func parseTemplate(name, source string, html bool) (executor, error) {
+	if html {
+		return htmltemplate.New(name).Funcs(htmltemplate.FuncMap(templateFuncs)).Parse(source)
+	}
+	return texttemplate.New(name).Funcs(texttemplate.FuncMap(templateFuncs)).Parse(source)
+}
+
+// textFormat returns a format rendered with its embedded template. Users can
+// upload their own templates for it.
+func textFormat(name, contentType string, html bool) Format {
+	file := "resume." + name + ".tmpl"
+	source, err := templateFiles.ReadFile("templates/" + file)
+	if err != nil {
+		panic(err)
+	}
+	builtin, err := parseTemplate(file, string(source), html)
+	if err != nil {
+		panic(err)
+	}
+
+	return Format{
+		Name:        name,
+		ContentType: contentType,
+		Custom:      true,
+		html:        html,
+		write: func(w io.Writer, resume models.Resume, _ string, _ Options) error {
+			return builtin.Execute(w, resume)
+		},
+	}
+}
+
+// ParseCustom checks that source is a template this format can render with
+func (f Format) ParseCustom(source string) error {
+	_, err := f.parseCustom(source)
+	return err
+}
+
+// WriteCustom exports the resume to w with a user's template. It stops when
+// ctx is cancelled or the output grows past MaxCustomOutputSize.
+func (f Format) WriteCustom(ctx context.Context, w io.Writer, resume models.Resume, source string) error {
+	t, err := f.parseCustom(source)
+	if err != nil {
+		return err
+	}
+	err = t.Execute(&limitedWriter{ctx: ctx, w: w, remaining: MaxCustomOutputSize}, resume)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if err != nil && !errors.Is(err, ErrInvalidTemplate) {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return err
+}
+
+// limitedWriter passes at most remaining bytes on to w, and nothing once ctx
+// is cancelled
+type limitedWriter struct {
+	ctx       context.Context
+	w         io.Writer
+	remaining int
+}
+
+// Write implements io.Writer
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if err := l.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if len(p) > l.remaining {
+		return 0, fmt.Errorf("%w: exports are limited to %d MB", ErrInvalidTemplate, MaxCustomOutputSize>>20)
+	}
+	l.remaining -= len(p)
+	return l.w.Write(p)
+}
+
+// parseCustom parses a user's template and runs it once against a sample
+// resume, which catches references to fields the resume does not have
+func (f Format) parseCustom(source string) (executor, error) {
+	if !f.Custom {
+		return nil, fmt.Errorf("%w: %s exports do not use custom templates", ErrInvalidTemplate, f.Name)
+	}
+	if len(source) > MaxCustomTemplateSize {
+		return nil, fmt.Errorf("%w: templates are limited to %d KB", ErrInvalidTemplate, MaxCustomTemplateSize>>10)
+	}
+
+	t, err := parseTemplate("custom", source, f.html)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	sample := &limitedWriter{ctx: context.Background(), w: io.Discard, remaining: MaxCustomOutputSize}
+	if err := t.Execute(sample, sampleResume); err != nil {
+		if errors.Is(err, ErrInvalidTemplate) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return t, nil
+}
+
+// sampleResume has every field and one entry in every list set, so that a
+// template that runs against it only fails on real resumes for data reasons
+var sampleResume = models.Resume{
+	BasicInfo: models.BasicInfo{
+		Name:     "Name",
+		Email:    "name@example.com",
+		Phone:    "Phone",
+		Address:  "Address",
+		Website:  "https://example.com",
+		LinkedIn: "https://linkedin.com/in/name",
+		GitHub:   "https://github.com/name",
+	},
+	Summary: "Summary",
+	Experience: []models.Experience{{
+		Company: "Company", Position: "Position", StartDate: "Start", EndDate: "End",
+		Description: "Description", Highlights: []string{"Highlight"},
+	}},
+	Education: []models.Education{{
+		Institution: "Institution", Degree: "Degree", Field: "Field",
+		StartDate: "Start", EndDate: "End", GPA: "GPA",
+	}},
+	Skills:       []models.Skill{{Name: "Skill", Level: "Level", Category: "Category"}},
+	Certificates: []models.Certificate{{Name: "Certificate", Issuer: "Issuer", IssueDate: "Date", ExpiryDate: "Date", URL: "https://example.com"}},
+	Projects: []models.Project{{
+		Name: "Project", Description: "Description", StartDate: "Start", EndDate: "End",
+		URL: "https://example.com", Technologies: []string{"Technology"},
+	}},
+}
@@ -0,0 +1,92 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"resume.in/backend/models"
+)
+
+func TestWriteCustom(t *testing.T) {
+	resume := models.Resume{BasicInfo: models.BasicInfo{Name: "Ada <Lovelace>"}}
+	for i := 0; i < 100; i++ {
+		resume.Skills = append(resume.Skills, models.Skill{Name: "Go"})
+	}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		format  string
+		ctx     context.Context
+		source  string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "renders the resume",
+			format: "md",
+			ctx:    context.Background(),
+			source: "# {{.BasicInfo.Name}}",
+			want:   "# Ada <Lovelace>",
+		},
+		{
+			name:   "escapes HTML",
+			format: "html",
+			ctx:    context.Background(),
+			source: "<h1>{{.BasicInfo.Name}}</h1>",
+			want:   "<h1>Ada &lt;Lovelace&gt;</h1>",
+		},
+		{
+			name:    "rejects unknown fields",
+			format:  "txt",
+			ctx:     context.Background(),
+			source:  "{{.Nickname}}",
+			wantErr: ErrInvalidTemplate,
+		},
+		{
+			name:    "stops output past the size limit",
+			format:  "txt",
+			ctx:     context.Background(),
+			source:  "{{range .Skills}}{{range $.Skills}}{{range $.Skills}}{{.Name}} and more{{end}}{{end}}{{end}}",
+			wantErr: ErrInvalidTemplate,
+		},
+		{
+			name:    "stops when the request is cancelled",
+			format:  "txt",
+			ctx:     cancelled,
+			source:  "{{.BasicInfo.Name}}",
+			wantErr: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := LookupFormat(tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			err = format.WriteCustom(tt.ctx, &buf, resume, tt.source)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("WriteCustom() error = %v, want %v", err, tt.wantErr)
+				}
+				if buf.Len() > MaxCustomOutputSize {
+					t.Errorf("WriteCustom() wrote %d bytes, over the limit of %d", buf.Len(), MaxCustomOutputSize)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriteCustom() error = %v", err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("WriteCustom() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
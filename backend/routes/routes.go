@@ -39,6 +39,7 @@ func SetupRouter(
 		{
 			// Registration endpoints
 			auth.POST("/register", authController.Register)
+			auth.POST("/login", authController.Login)
 			auth.GET("/google/register", authController.GoogleRegister)
 			
 			// Google OAuth login endpoints (unified callback handles both login and registration)
@@ -63,18 +64,43 @@ func SetupRouter(
 			chat.Use(middleware.AuthMiddleware(cfg.JWTSecret))
 			{
 				chat.POST("/message", chatbotController.SendMessage)
+				chat.POST("/message/stream", chatbotController.SendMessageStream)
+				chat.GET("/sessions", chatbotController.ListSessions)
 				chat.GET("/history/:sessionId", chatbotController.GetChatHistory)
 				chat.POST("/document", chatbotController.UploadDocument)
+				chat.GET("/documents", chatbotController.ListDocuments)
+				chat.DELETE("/documents", chatbotController.DeleteDocuments)
+				chat.GET("/documents/:id", chatbotController.GetDocument)
+				chat.PUT("/documents/:id", chatbotController.ReplaceDocument)
+				chat.DELETE("/documents/:id", chatbotController.DeleteDocument)
 				chat.POST("/generate-resume", chatbotController.GenerateATSResume)
+				chat.POST("/save-resume", chatbotController.SaveChatResume)
 			}
 		}
 
+		// Resume templates (public)
+		api.GET("/templates", resumeController.ListTemplates)
+
+		// Custom export templates (protected)
+		customTemplates := api.Group("/templates/custom")
+		customTemplates.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+		{
+			customTemplates.GET("", resumeController.ListCustomTemplates)
+			customTemplates.POST("", resumeController.UploadCustomTemplate)
+			customTemplates.DELETE("/:id", resumeController.DeleteCustomTemplate)
+		}
+
 		// Resume endpoints (protected)
 		resume := api.Group("/resumes")
 		resume.Use(middleware.AuthMiddleware(cfg.JWTSecret))
 		{
 			resume.GET("", resumeController.GetResumes)
 			resume.GET("/:id", resumeController.GetResume)
+			resume.GET("/:id/export", resumeController.ExportResume)
+			resume.GET("/:id/pdf", resumeController.ExportResume)
+			resume.GET("/:id/export/jsonresume", resumeController.ExportJSONResume)
+			resume.POST("/import", resumeController.ImportResume)
+			resume.POST("/import/jsonresume", resumeController.ImportJSONResume)
 			resume.POST("", resumeController.CreateResume)
 			resume.PUT("/:id", resumeController.UpdateResume)
 			resume.DELETE("/:id", resumeController.DeleteResume)
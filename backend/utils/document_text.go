@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// MIME types of the documents DocumentText can read
+const (
+	MIMETypePDF      = "application/pdf"
+	MIMETypeDOCX     = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MIMETypeText     = "text/plain"
+	MIMETypeMarkdown = "text/markdown"
+)
+
+var (
+	// ErrUnsupportedDocument is returned for files that are not PDF, DOCX, plain text or Markdown
+	ErrUnsupportedDocument = errors.New("documents must be PDF, DOCX, plain text or Markdown files")
+	// ErrEmptyDocument is returned for DOCX and text files without any text
+	ErrEmptyDocument = errors.New("the document has no text")
+)
+
+// maxDOCXTextSize limits how much of a DOCX file's document.xml is decompressed
+const maxDOCXTextSize = 50 << 20
+
+// DocumentType returns the MIME type of an uploaded document. The file
+// extension decides, so that Markdown is told apart from plain text, and the
+// content is sniffed for files without a known extension.
+func DocumentType(filename string, data []byte) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".pdf":
+		return MIMETypePDF, nil
+	case ".docx":
+		return MIMETypeDOCX, nil
+	case ".txt", ".text":
+		return MIMETypeText, nil
+	case ".md", ".markdown":
+		return MIMETypeMarkdown, nil
+	}
+
+	switch detected := http.DetectContentType(data); {
+	case detected == MIMETypePDF:
+		return MIMETypePDF, nil
+	case strings.HasPrefix(detected, "text/plain"):
+		return MIMETypeText, nil
+	}
+	return "", ErrUnsupportedDocument
+}
+
+// DocumentText extracts the text of a document with one of the MIME types
+// returned by DocumentType
+func DocumentText(mimeType string, data []byte) (string, error) {
+	var text string
+	switch mimeType {
+	case MIMETypePDF:
+		return PDFText(data)
+	case MIMETypeDOCX:
+		var err error
+		if text, err = DOCXText(data); err != nil {
+			return "", err
+		}
+	case MIMETypeText, MIMETypeMarkdown:
+		if !utf8.Valid(data) {
+			return "", errors.New("text documents must be UTF-8")
+		}
+		text = strings.TrimPrefix(string(data), "\ufeff")
+	default:
+		return "", ErrUnsupportedDocument
+	}
+
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if text == "" {
+		return "", ErrEmptyDocument
+	}
+	return text, nil
+}
+
+// DOCXText extracts the text of a Word document's body with one line per paragraph
+func DOCXText(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to read DOCX: %v", err)
+	}
+
+	for _, f := range archive.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", fmt.Errorf("failed to read DOCX: %v", err)
+		}
+		defer rc.Close()
+		return docxText(io.LimitReader(rc, maxDOCXTextSize))
+	}
+	return "", errors.New("failed to read DOCX: word/document.xml is missing")
+}
+
+// docxText collects the text runs of word/document.xml. Tabs and breaks
+// inside a paragraph are kept, and every paragraph ends a line.
+func docxText(r io.Reader) (string, error) {
+	var text strings.Builder
+	decoder := xml.NewDecoder(r)
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read DOCX: %v", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteByte('\t')
+			case "br", "cr":
+				text.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+	return text.String(), nil
+}
@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// docxFile returns a Word document whose zip holds the given parts
+func docxFile(t *testing.T, parts map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// wordDocument wraps body in the elements of word/document.xml
+func wordDocument(body string) string {
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		body + `</w:body></w:document>`
+}
+
+func TestDocumentType(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n1 0 obj\n<<>>\nendobj\n")
+	text := []byte("Ada Lovelace\nMathematician\n")
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	docx := docxFile(t, map[string]string{"word/document.xml": wordDocument("")})
+
+	tests := []struct {
+		name     string
+		filename string
+		data     []byte
+		want     string
+		wantErr  error
+	}{
+		{name: "PDF by extension", filename: "resume.pdf", data: text, want: MIMETypePDF},
+		{name: "extensions ignore case", filename: "RESUME.PDF", data: pdf, want: MIMETypePDF},
+		{name: "DOCX by extension", filename: "resume.docx", data: docx, want: MIMETypeDOCX},
+		{name: "text by extension", filename: "resume.txt", data: text, want: MIMETypeText},
+		{name: "Markdown by extension", filename: "resume.md", data: text, want: MIMETypeMarkdown},
+		{name: "long Markdown extension", filename: "resume.markdown", data: text, want: MIMETypeMarkdown},
+		{name: "PDF sniffed without an extension", filename: "resume", data: pdf, want: MIMETypePDF},
+		{name: "PDF sniffed behind an unknown extension", filename: "resume.bin", data: pdf, want: MIMETypePDF},
+		{name: "text sniffed without an extension", filename: "resume", data: text, want: MIMETypeText},
+		{name: "DOCX needs its extension", filename: "resume", data: docx, wantErr: ErrUnsupportedDocument},
+		{name: "images are rejected", filename: "photo.png", data: png, wantErr: ErrUnsupportedDocument},
+		{name: "old Word files are rejected", filename: "resume.doc", data: []byte("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1"), wantErr: ErrUnsupportedDocument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DocumentType(tt.filename, tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DocumentType(%q) error = %v, want %v", tt.filename, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DocumentType(%q) = %q, want %q", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDOCXText(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "one line per paragraph",
+			data: docxFile(t, map[string]string{"word/document.xml": wordDocument(
+				`<w:p><w:r><w:t>Ada Lovelace</w:t></w:r></w:p>` +
+					`<w:p><w:r><w:t xml:space="preserve">Analyst, </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>Analytical Engines</w:t></w:r></w:p>`,
+			)}),
+			want: "Ada Lovelace\nAnalyst, Analytical Engines\n",
+		},
+		{
+			name: "tabs and breaks are kept",
+			data: docxFile(t, map[string]string{"word/document.xml": wordDocument(
+				`<w:p><w:r><w:t>1842</w:t><w:tab/><w:t>Notes</w:t><w:br/><w:t>Bernoulli &amp; more</w:t></w:r></w:p>`,
+			)}),
+			want: "1842\tNotes\nBernoulli & more\n",
+		},
+		{
+			name: "other parts are ignored",
+			data: docxFile(t, map[string]string{
+				"word/document.xml": wordDocument(`<w:p><w:r><w:t>Body</w:t></w:r></w:p>`),
+				"word/header1.xml":  wordDocument(`<w:p><w:r><w:t>Header</w:t></w:r></w:p>`),
+				"word/styles.xml":   `<w:styles/>`,
+			}),
+			want: "Body\n",
+		},
+		{
+			name:    "missing document part",
+			data:    docxFile(t, map[string]string{"word/styles.xml": `<w:styles/>`}),
+			wantErr: true,
+		},
+		{
+			name:    "invalid XML",
+			data:    docxFile(t, map[string]string{"word/document.xml": `<w:document><w:body>`}),
+			wantErr: true,
+		},
+		{
+			name:    "not a zip",
+			data:    []byte("Ada Lovelace"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DOCXText(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("DOCXText() = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DOCXText() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DOCXText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocumentText(t *testing.T) {
+	tests := []struct {
+		name     string
+		mimeType string
+		data     []byte
+		want     string
+		wantErr  error
+	}{
+		{name: "text is trimmed", mimeType: MIMETypeText, data: []byte("\ufeff  Ada\r\nLovelace \n"), want: "Ada\nLovelace"},
+		{name: "Markdown is kept as written", mimeType: MIMETypeMarkdown, data: []byte("# Ada\n\n- Go"), want: "# Ada\n\n- Go"},
+		{
+			name:     "DOCX",
+			mimeType: MIMETypeDOCX,
+			data:     docxFile(t, map[string]string{"word/document.xml": wordDocument(`<w:p><w:r><w:t>Ada</w:t></w:r></w:p>`)}),
+			want:     "Ada",
+		},
+		{
+			name:     "DOCX without text",
+			mimeType: MIMETypeDOCX,
+			data:     docxFile(t, map[string]string{"word/document.xml": wordDocument(`<w:p/>`)}),
+			wantErr:  ErrEmptyDocument,
+		},
+		{name: "blank text", mimeType: MIMETypeText, data: []byte(" \n\t"), wantErr: ErrEmptyDocument},
+		{name: "unsupported type", mimeType: "image/png", data: []byte("Ada"), wantErr: ErrUnsupportedDocument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DocumentText(tt.mimeType, tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DocumentText() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DocumentText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := DocumentText(MIMETypeText, []byte("Ada \xff")); err == nil {
+		t.Error("DocumentText() accepted text that is not UTF-8")
+	}
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// ErrNoPDFText is returned for PDFs without extractable text, such as scanned images
+var ErrNoPDFText = errors.New("the PDF has no extractable text")
+
+// PDFText extracts the text of a PDF with one line of output per line of text
+// and a blank line between pages. Lines keep the order the PDF draws them in,
+// which keeps the columns of multi-column layouts apart.
+func PDFText(data []byte) (text string, err error) {
+	// The PDF reader panics on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			text, err = "", fmt.Errorf("failed to read PDF: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to read PDF: %v", err)
+	}
+
+	var pages []string
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		if lines := pageLines(page.Content().Text); len(lines) > 0 {
+			pages = append(pages, strings.Join(lines, "\n"))
+		}
+	}
+
+	text = strings.Join(pages, "\n\n")
+	if strings.TrimSpace(text) == "" {
+		return "", ErrNoPDFText
+	}
+	return text, nil
+}
+
+// pageLines joins the glyphs of a page into lines. A glyph starts a new line
+// when its baseline moves, and is preceded by a space when it starts well
+// to the right of where the previous glyph ended.
+func pageLines(glyphs []pdf.Text) []string {
+	var lines []string
+	var line strings.Builder
+	flush := func() {
+		if s := strings.Join(strings.Fields(line.String()), " "); s != "" {
+			lines = append(lines, s)
+		}
+		line.Reset()
+	}
+
+	for i, g := range glyphs {
+		if i > 0 {
+			prev := glyphs[i-1]
+			switch {
+			case math.Abs(g.Y-prev.Y) > 1:
+				flush()
+			case g.X > prev.X+prev.W+g.FontSize*0.15:
+				line.WriteByte(' ')
+			}
+		}
+		line.WriteString(g.S)
+	}
+	flush()
+
+	return lines
+}